@@ -3,6 +3,7 @@ package parse
 import (
 	"errors"
 	"fmt"
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
 )
 
 const ExcelRowOffset = 1
@@ -12,9 +13,29 @@ type AggItem struct {
 	SheetName string
 	FileName string
 	FilePath string
+	// RowIdx is the zero based worksheet row index of the item's source row.
 	RowIdx int
+	// Date1904 is true when the source workbook uses the 1904 date system.
+	Date1904 bool
 	OriginalFormat []string
 	DecimalFormat []string
+	// Hyperlinks holds the hyperlink target of each cell in the same order as the formats.
+	// Nil unless the sheet was parsed with ParseOptions.Hyperlinks.
+	Hyperlinks []string
+	// ColOrigins holds the worksheet column number of each cell in the same order as the formats.
+	// Zero when the source sheet does not have the column.
+	ColOrigins []int
+}
+
+// CellAddress returns the worksheet address of the item's source cell with the given index.
+func (ai AggItem)CellAddress(c int)(string, error){
+	if c < 0 || c >= len(ai.OriginalFormat){
+		return "", ErrInvalidIndices
+	}
+	if c >= len(ai.ColOrigins) || ai.ColOrigins[c] == 0 || ai.RowIdx < 0{
+		return "", ErrNoCellOrigin
+	}
+	return excelize.CoordinatesToCellName(ai.ColOrigins[c], ai.RowIdx + ExcelRowOffset)
 }
 
 // AggregatedParse represents an aggregation similar
@@ -40,7 +61,15 @@ func (ai AggregateInfo)Header()[]string{
 }
 
 func dataFromInfo(data [][]string, startRow int, startCol int)[][]string{
-	return data[startRow + 1:][startCol:]
+	rows := make([][]string, 0, len(data))
+	for _, row := range data[startRow + 1:]{
+		if startCol > len(row){
+			rows = append(rows, []string{})
+			continue
+		}
+		rows = append(rows, row[startCol:])
+	}
+	return rows
 }
 // DecimalFormattedData uses the aggregate info to return a sheets
 // decimal formatted data.
@@ -52,6 +81,17 @@ func (ai AggregateInfo)DecimalFormattedData()[][]string{
 func (ai AggregateInfo)OriginalFormattedData()[][]string{
 	return dataFromInfo(ai.Sheet.Original, ai.StartRow, ai.StartCol)
 }
+// hyperlinkData uses the aggregate info to return the hyperlink targets of a sheet's data.
+func (ai AggregateInfo)hyperlinkData()[][]string{
+	links := make([][]string, len(ai.Sheet.Original))
+	for r := range ai.Sheet.Original{
+		links[r] = make([]string, len(ai.Sheet.Original[r]))
+		for c := range ai.Sheet.Original[r]{
+			links[r][c], _ = ai.Sheet.Hyperlink(r, c)
+		}
+	}
+	return dataFromInfo(links, ai.StartRow, ai.StartCol)
+}
 
 // CanAggregate returns whether the aggregate info is able to
 // be aggregated.
@@ -98,16 +138,32 @@ func allHeaderIndices(ais ...AggregateInfo)(map[string]int, error){
 	return posMap, nil
 }
 
-// createAggregateRowMapper returns a function which creates an aggregate
-// row from a sheet's row after all the column headers of every sheet
-// to be aggregated are considered.
-func createAggregateRowMapper(ai AggregateInfo, aggPosMap map[string]int)func([]string)[]string{
+// headerPositions returns the index of the first column with each header of the info.
+func headerPositions(ai AggregateInfo)map[string]int{
 	sheetPosMap := make(map[string]int)
 	for idx, header := range ai.Header(){
 		if _, ok := sheetPosMap[header];!ok{
 			sheetPosMap[header] = idx
 		}
 	}
+	return sheetPosMap
+}
+
+// aggregateColumnOrigins returns the worksheet column number of each column
+// of the aggregation within the info's sheet.
+func aggregateColumnOrigins(ai AggregateInfo, aggPosMap map[string]int)[]int{
+	origins := make([]int, len(aggPosMap))
+	for header, sheetIdx := range headerPositions(ai){
+		origins[aggPosMap[header]] = ai.Sheet.ColOrigin(ai.StartCol + sheetIdx)
+	}
+	return origins
+}
+
+// createAggregateRowMapper returns a function which creates an aggregate
+// row from a sheet's row after all the column headers of every sheet
+// to be aggregated are considered.
+func createAggregateRowMapper(ai AggregateInfo, aggPosMap map[string]int)func([]string)[]string{
+	sheetPosMap := headerPositions(ai)
 	return func(r []string)[]string{
 		aggRow := make([]string, len(aggPosMap))
 		for header, aggIdx := range aggPosMap{
@@ -115,7 +171,9 @@ func createAggregateRowMapper(ai AggregateInfo, aggPosMap map[string]int)func([]
 				continue
 			}
 			sheetIdx := sheetPosMap[header]
-			aggRow[aggIdx] = r[sheetIdx]
+			if sheetIdx < len(r){
+				aggRow[aggIdx] = r[sheetIdx]
+			}
 		}
 		return aggRow
 	}
@@ -137,14 +195,24 @@ func AggregateAllSheets(ais ...AggregateInfo)(AggregatedParse, error){
 	aggItems := make([]AggItem, 0)
 	for _, ai := range ais{
 		mapper := createAggregateRowMapper(ai, aggPosMap)
+		colOrigins := aggregateColumnOrigins(ai, aggPosMap)
+		var links [][]string
+		if ai.Sheet.Hyperlinks != nil{
+			links = ai.hyperlinkData()
+		}
 		for i := range ai.OriginalFormattedData(){
 			aggItem := AggItem{
 				SheetName:      ai.Sheet.Name,
 				FileName: ai.Sheet.FileName,
 				FilePath: ai.Sheet.Path,
-				RowIdx:         i + ExcelRowOffset,
+				RowIdx:         ai.Sheet.RowOrigin(ai.StartRow + 1 + i) - ExcelRowOffset,
+				Date1904: ai.Sheet.Date1904,
 				OriginalFormat: mapper(ai.OriginalFormattedData()[i]),
 				DecimalFormat:  mapper(ai.DecimalFormattedData()[i]),
+				ColOrigins: colOrigins,
+			}
+			if links != nil{
+				aggItem.Hyperlinks = mapper(links[i])
 			}
 			aggItems = append(aggItems, aggItem)
 		}
@@ -0,0 +1,198 @@
+package parse
+
+import (
+	"encoding/xml"
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"path"
+	"strings"
+)
+
+// CellComment is the text and author of a cell comment.
+type CellComment struct {
+	Author string
+	Text   string
+}
+
+// sheetRelationships is used to read the targets of a sheet's relationships
+// which have not been loaded by excelize.
+type sheetRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// applyAnnotationOptions loads the comments, hyperlinks and drop down lists of the sheet.
+func (ps *ParsedSheet) applyAnnotationOptions(f *excelize.File, opts ParseOptions) error {
+	if opts.Comments {
+		ps.Comments = make(map[string]CellComment)
+		for _, comment := range f.GetComments()[ps.Name] {
+			ps.Comments[comment.Ref] = CellComment{Author: comment.Author, Text: commentText(comment)}
+		}
+	}
+	if !opts.Hyperlinks && !opts.Validations {
+		return nil
+	}
+	wsPath, err := loadWorksheet(f, ps.Name)
+	if err != nil {
+		return err
+	}
+	ws := f.Sheet[wsPath]
+	if opts.Hyperlinks {
+		ps.Hyperlinks = make(map[string]string)
+		if ws.Hyperlinks != nil {
+			targets := relationshipTargets(f, wsPath)
+			for _, link := range ws.Hyperlinks.Hyperlink {
+				target := link.Location
+				if link.RID != "" {
+					target = targets[link.RID]
+				}
+				for _, axis := range ps.refCells(link.Ref, false) {
+					ps.Hyperlinks[axis] = target
+				}
+			}
+		}
+	}
+	if opts.Validations {
+		ps.Validations = make(map[string][]string)
+		if ws.DataValidations != nil {
+			for _, dv := range ws.DataValidations.DataValidation {
+				if dv == nil || dv.Type != "list" {
+					continue
+				}
+				list, ok := validationList(f, ps.Name, dv.Formula1)
+				if !ok {
+					continue
+				}
+				for _, ref := range strings.Fields(dv.Sqref) {
+					for _, axis := range ps.refCells(ref, true) {
+						ps.Validations[axis] = list
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// commentText returns the text of a comment without the author heading
+// Excel writes at the start of the comment e.g. "Author:\n".
+func commentText(comment excelize.Comment) string {
+	if comment.Author == "" || !strings.HasPrefix(comment.Text, comment.Author) {
+		return comment.Text
+	}
+	return strings.TrimLeft(strings.TrimPrefix(comment.Text, comment.Author), ": \r\n")
+}
+
+// relationshipTargets returns the target of each of a worksheet's relationships keyed by id.
+func relationshipTargets(f *excelize.File, wsPath string) map[string]string {
+	relsPath := path.Join(path.Dir(wsPath), "_rels", path.Base(wsPath)+".rels")
+	targets := make(map[string]string)
+	if rels, ok := f.Relationships[relsPath]; ok && rels != nil {
+		for _, rel := range rels.Relationships {
+			targets[rel.ID] = rel.Target
+		}
+		return targets
+	}
+	var rels sheetRelationships
+	if err := xml.Unmarshal(f.XLSX[relsPath], &rels); err != nil {
+		return targets
+	}
+	for _, rel := range rels.Relationships {
+		targets[rel.ID] = rel.Target
+	}
+	return targets
+}
+
+// refCells returns the address of every cell of a reference such as "B2" or "B2:B10".
+// When clip is true only cells within the parsed data are returned, which avoids
+// expanding references covering whole columns.
+func (ps *ParsedSheet) refCells(ref string, clip bool) []string {
+	bounds := strings.Split(strings.ReplaceAll(ref, "$", ""), ":")
+	startCol, startRow, err := excelize.CellNameToCoordinates(bounds[0])
+	if err != nil {
+		return nil
+	}
+	endCol, endRow := startCol, startRow
+	if len(bounds) == 2 {
+		if endCol, endRow, err = excelize.CellNameToCoordinates(bounds[1]); err != nil {
+			return nil
+		}
+	}
+	if clip {
+		if endRow > len(ps.Original) {
+			endRow = len(ps.Original)
+		}
+		if len(ps.Original) > 0 && endCol > len(ps.Original[0]) {
+			endCol = len(ps.Original[0])
+		}
+	}
+	cells := make([]string, 0)
+	for r := startRow; r <= endRow; r++ {
+		for c := startCol; c <= endCol; c++ {
+			axis, _ := excelize.CoordinatesToCellName(c, r)
+			cells = append(cells, axis)
+		}
+	}
+	return cells
+}
+
+// validationList returns the values of a list data validation.
+// The list may be written in the formula e.g. "Open,Closed" or refer to
+// a range of cells or a defined name.
+func validationList(f *excelize.File, sheet, formulaXML string) ([]string, bool) {
+	var formula1 struct {
+		Value string `xml:",chardata"`
+	}
+	if err := xml.Unmarshal([]byte(formulaXML), &formula1); err != nil {
+		return nil, false
+	}
+	formula := strings.TrimSpace(formula1.Value)
+	if strings.HasPrefix(formula, `"`) && strings.HasSuffix(formula, `"`) && len(formula) >= 2 {
+		// Quotes within the list are doubled.
+		return strings.Split(strings.ReplaceAll(formula[1:len(formula)-1], `""`, `"`), ","), true
+	}
+	for _, dn := range f.GetDefinedName() {
+		if dn.Name == formula {
+			formula = strings.TrimPrefix(dn.RefersTo, "=")
+			break
+		}
+	}
+	if idx := strings.LastIndex(formula, "!"); idx >= 0 {
+		sheet = strings.ReplaceAll(strings.Trim(formula[:idx], "'"), "''", "'")
+		formula = formula[idx+1:]
+	}
+	list := make([]string, 0)
+	for _, axis := range (&ParsedSheet{}).refCells(formula, false) {
+		v, err := f.GetCellValue(sheet, axis)
+		if err != nil {
+			return nil, false
+		}
+		list = append(list, v)
+	}
+	return list, len(list) > 0
+}
+
+// Comment returns the comment of the cell at the pair of indices.
+//	The sheet must be parsed with ParseOptions.Comments.
+func (ps *ParsedSheet) Comment(r, c int) (CellComment, bool) {
+	axis, _ := ps.CellAddress(r, c)
+	comment, ok := ps.Comments[axis]
+	return comment, ok
+}
+
+// Hyperlink returns the target of the hyperlink of the cell at the pair of indices.
+//	The sheet must be parsed with ParseOptions.Hyperlinks.
+func (ps *ParsedSheet) Hyperlink(r, c int) (string, bool) {
+	axis, _ := ps.CellAddress(r, c)
+	target, ok := ps.Hyperlinks[axis]
+	return target, ok
+}
+
+// Validation returns the drop down list values of the cell at the pair of indices.
+//	The sheet must be parsed with ParseOptions.Validations.
+func (ps *ParsedSheet) Validation(r, c int) ([]string, bool) {
+	axis, _ := ps.CellAddress(r, c)
+	list, ok := ps.Validations[axis]
+	return list, ok
+}
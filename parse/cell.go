@@ -0,0 +1,284 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"strings"
+)
+
+// ErrCellError is returned when parsing a cell which contains an Excel error value e.g. #N/A.
+var ErrCellError = errors.New("sheetParse: cell contains an error value")
+
+// CellType is the type of value stored in a cell.
+type CellType int
+
+const (
+	CellTypeEmpty CellType = iota
+	CellTypeNumber
+	CellTypeString
+	CellTypeBool
+	CellTypeError
+	// CellTypeDate is used for cells stored as ISO 8601 dates which is rare.
+	// Dates are normally numbers with a date number format.
+	CellTypeDate
+)
+
+func (ct CellType) String() string {
+	switch ct {
+	case CellTypeNumber:
+		return "number"
+	case CellTypeString:
+		return "string"
+	case CellTypeBool:
+		return "bool"
+	case CellTypeError:
+		return "error"
+	case CellTypeDate:
+		return "date"
+	}
+	return "empty"
+}
+
+// Cell describes the value of a cell as it is stored in the workbook
+// before any formatting is applied.
+type Cell struct {
+	Type CellType
+	// Raw is the stored value. The text for string cells and "1" or "0" for bool cells.
+	Raw string
+	// NumFmtID is the id of the cell's number format.
+	NumFmtID int
+	// NumFmtCode is the code of the cell's number format e.g. "0.00%".
+	NumFmtCode string
+	// Formula is the formula text without the leading "=". Empty when the cell is not a formula.
+	// Cells sharing a formula only have the text on the first cell unless ParseOptions.Formulas is set.
+	Formula string
+	// Error is the error value e.g. #N/A or #DIV/0! of error cells.
+	Error string
+	// Style is the fill and font of the cell.
+	Style CellStyle
+
+	hasFormula bool
+}
+
+// IsFormula returns whether the cell's value is calculated by a formula.
+func (c Cell) IsFormula() bool {
+	return c.Formula != "" || c.hasFormula
+}
+
+// IsDate returns whether the cell holds a date or time, either stored as a date or
+// as a number with a date or time number format e.g. "yyyy-mm-dd" or "h:mm".
+//	Elapsed times such as "[h]:mm" are not dates.
+func (c Cell) IsDate() bool {
+	if c.Type == CellTypeDate {
+		return true
+	}
+	return c.Type == CellTypeNumber && isDateNumFmt(c.NumFmtCode)
+}
+
+// isDateNumFmt returns whether a number format code has date or time parts outside of
+// its quoted text, escaped characters and bracketed sections.
+func isDateNumFmt(code string) bool {
+	// Only the format of positive numbers is used.
+	code = strings.SplitN(code, ";", 2)[0]
+	for i := 0; i < len(code); i++ {
+		switch ch := code[i]; ch {
+		case '"':
+			if end := strings.IndexByte(code[i+1:], '"'); end >= 0 {
+				i += end + 1
+			}
+		case '[':
+			if end := strings.IndexByte(code[i+1:], ']'); end >= 0 {
+				if strings.Trim(strings.ToLower(code[i+1:i+1+end]), "hms") == "" {
+					return false
+				}
+				i += end + 1
+			}
+		case '\\', '_', '*':
+			i++
+		case 'y', 'Y', 'd', 'D', 'm', 'M', 'h', 'H', 's', 'S':
+			return true
+		}
+	}
+	return false
+}
+
+// builtInNumFmtCodes maps the ids of built in number formats to their codes.
+var builtInNumFmtCodes = map[int]string{
+	0:  "General",
+	1:  "0",
+	2:  "0.00",
+	3:  "#,##0",
+	4:  "#,##0.00",
+	9:  "0%",
+	10: "0.00%",
+	11: "0.00E+00",
+	12: "# ?/?",
+	13: "# ??/??",
+	14: "mm-dd-yy",
+	15: "d-mmm-yy",
+	16: "d-mmm",
+	17: "mmm-yy",
+	18: "h:mm AM/PM",
+	19: "h:mm:ss AM/PM",
+	20: "h:mm",
+	21: "h:mm:ss",
+	22: "m/d/yy h:mm",
+	37: "#,##0 ;(#,##0)",
+	38: "#,##0 ;[Red](#,##0)",
+	39: "#,##0.00;(#,##0.00)",
+	40: "#,##0.00;[Red](#,##0.00)",
+	45: "mm:ss",
+	46: "[h]:mm:ss",
+	47: "mmss.0",
+	48: "##0.0E+0",
+	49: "@",
+}
+
+// worksheetPath returns the path of a sheet's XML within the workbook package.
+func worksheetPath(f *excelize.File, sheet string) (string, bool) {
+	rels, ok := f.Relationships["xl/_rels/workbook.xml.rels"]
+	if !ok || rels == nil || f.WorkBook == nil {
+		return "", false
+	}
+	for _, s := range f.WorkBook.Sheets.Sheet {
+		if s.Name != sheet {
+			continue
+		}
+		for _, rel := range rels.Relationships {
+			if rel.ID != s.ID {
+				continue
+			}
+			pathInfo := strings.Split(rel.Target, "/")
+			if len(pathInfo) < 2 {
+				return "", false
+			}
+			return "xl/" + strings.Join(pathInfo[len(pathInfo)-2:], "/"), true
+		}
+	}
+	return "", false
+}
+
+// loadWorksheet makes sure the sheet's XML is loaded into f.Sheet and returns its path.
+func loadWorksheet(f *excelize.File, sheet string) (string, error) {
+	// Reading a cell's style loads the worksheet so that its XML can be read.
+	if _, err := f.GetCellStyle(sheet, "A1"); err != nil {
+		return "", err
+	}
+	path, ok := worksheetPath(f, sheet)
+	if !ok {
+		return "", fmt.Errorf("sheetParse: worksheet %s could not be found", sheet)
+	}
+	if ws, ok := f.Sheet[path]; !ok || ws == nil {
+		return "", fmt.Errorf("sheetParse: worksheet %s could not be read", sheet)
+	}
+	return path, nil
+}
+
+// numFmt returns the number format id and code of a style.
+func numFmt(f *excelize.File, styleID int) (int, string) {
+	if f.Styles == nil || f.Styles.CellXfs == nil || styleID >= len(f.Styles.CellXfs.Xf) {
+		return 0, builtInNumFmtCodes[0]
+	}
+	xf := f.Styles.CellXfs.Xf[styleID]
+	if xf.NumFmtID == nil {
+		return 0, builtInNumFmtCodes[0]
+	}
+	id := *xf.NumFmtID
+	if f.Styles.NumFmts != nil {
+		for _, nf := range f.Styles.NumFmts.NumFmt {
+			if nf.NumFmtID == id {
+				return id, nf.FormatCode
+			}
+		}
+	}
+	return id, builtInNumFmtCodes[id]
+}
+
+// cellType returns the CellType of a cell XML type attribute and value.
+func cellType(t, v string) CellType {
+	switch t {
+	case "s", "str", "inlineStr":
+		return CellTypeString
+	case "b":
+		return CellTypeBool
+	case "e":
+		return CellTypeError
+	case "d":
+		return CellTypeDate
+	}
+	if v == "" {
+		return CellTypeEmpty
+	}
+	return CellTypeNumber
+}
+
+// makeCells reads the stored value of every cell within the shape of the formatted cells.
+// Must be called before any styles are changed to keep the original number formats and styles.
+func makeCells(f *excelize.File, sheet string, formatted [][]string) ([][]Cell, error) {
+	cells := make([][]Cell, len(formatted))
+	for i := range formatted {
+		cells[i] = make([]Cell, len(formatted[i]))
+	}
+	path, err := loadWorksheet(f, sheet)
+	if err != nil {
+		return nil, err
+	}
+	ws := f.Sheet[path]
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			col, r, err := excelize.CellNameToCoordinates(c.R)
+			if err != nil || r > len(cells) || col > len(cells[r-1]) {
+				continue
+			}
+			cell := Cell{
+				Type: cellType(c.T, c.V),
+				Raw:  c.V,
+			}
+			cell.NumFmtID, cell.NumFmtCode = numFmt(f, c.S)
+			cell.Style = cellStyle(f, c.S)
+			switch cell.Type {
+			case CellTypeString:
+				cell.Raw = formatted[r-1][col-1]
+			case CellTypeError:
+				cell.Error = c.V
+			}
+			if c.F != nil {
+				cell.Formula = c.F.Content
+				cell.hasFormula = true
+			}
+			cells[r-1][col-1] = cell
+		}
+	}
+	return cells, nil
+}
+
+// cellErr returns an error wrapping ErrCellError when the cell contains an Excel error value.
+func (ps *ParsedSheet) cellErr(r, c int) error {
+	if r >= len(ps.Cells) || c >= len(ps.Cells[r]) {
+		return nil
+	}
+	if cell := ps.Cells[r][c]; cell.Type == CellTypeError {
+		return fmt.Errorf("%w: %s", ErrCellError, cell.Error)
+	}
+	return nil
+}
+
+// Cell returns the stored value of the cell at the pair of indices.
+func (ps *ParsedSheet) Cell(r, c int) (Cell, error) {
+	if err := ps.indexErr(r, c); err != nil {
+		return Cell{}, err
+	}
+	if r >= len(ps.Cells) || c >= len(ps.Cells[r]) {
+		return Cell{}, nil
+	}
+	return ps.Cells[r][c], nil
+}
+
+// textCell returns a string Cell for values which are not read from a workbook.
+func textCell(s string) Cell {
+	if s == "" {
+		return Cell{}
+	}
+	return Cell{Type: CellTypeString, Raw: s, NumFmtCode: builtInNumFmtCodes[0]}
+}
@@ -0,0 +1,312 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrUnknownHeader is returned when no column of a parsed sheet has the given header.
+var ErrUnknownHeader = errors.New("sheetParse: unknown column header")
+
+// ErrDuplicateHeader is returned when a selection or reorder names the same header twice
+// or when the headers of a summary would not be unique.
+var ErrDuplicateHeader = errors.New("sheetParse: column header given more than once")
+
+// Column is a view of a single column of a ParsedSheet found by its header.
+//	The view holds the column's index so is invalidated by operations which
+//	remove, add or reorder columns.
+type Column struct {
+	Header string
+	Index  int
+	sheet  *ParsedSheet
+}
+
+// ColumnIndex returns the index of the first column with the header.
+func (ps *ParsedSheet) ColumnIndex(header string) (int, error) {
+	if len(ps.Original) > 0 {
+		for c, h := range ps.Original[0] {
+			if h == header {
+				return c, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUnknownHeader, header)
+}
+
+// Column returns a view of the first column with the header.
+func (ps *ParsedSheet) Column(header string) (Column, error) {
+	c, err := ps.ColumnIndex(header)
+	if err != nil {
+		return Column{}, err
+	}
+	return Column{Header: header, Index: c, sheet: ps}, nil
+}
+
+// Len returns the number of data rows of the column.
+func (col Column) Len() int {
+	if len(col.sheet.Original) == 0 {
+		return 0
+	}
+	return len(col.sheet.Original) - 1
+}
+
+// Values returns the originally formatted values of the column's data rows.
+func (col Column) Values() []string {
+	values := make([]string, 0, col.Len())
+	for r := 1; r < len(col.sheet.Original); r++ {
+		values = append(values, col.sheet.Original[r][col.Index])
+	}
+	return values
+}
+
+// DecimalValues returns the decimal formatted values of the column's data rows.
+func (col Column) DecimalValues() []string {
+	values := make([]string, 0, col.Len())
+	for r := 1; r < len(col.sheet.DecimalFormat); r++ {
+		values = append(values, col.sheet.DecimalFormat[r][col.Index])
+	}
+	return values
+}
+
+// Floats parses every data row of the column as a float64.
+//	The error of the first cell which can not be parsed is returned with its worksheet address.
+func (col Column) Floats() ([]float64, error) {
+	floats := make([]float64, 0, col.Len())
+	for r := 1; r < len(col.sheet.Original); r++ {
+		f, err := col.sheet.ParsedFloat(r, col.Index)
+		if err != nil {
+			return nil, col.sheet.cellLocatedErr(r, col.Index, err)
+		}
+		floats = append(floats, f)
+	}
+	return floats, nil
+}
+
+// cellLocatedErr adds the worksheet address of the cell at the pair of indices to err.
+func (ps *ParsedSheet) cellLocatedErr(r, c int, err error) error {
+	addr, addrErr := ps.CellAddress(r, c)
+	if addrErr != nil {
+		return err
+	}
+	return fmt.Errorf("%s: %w", addr, err)
+}
+
+// GetString returns the originally formatted value of the row's cell in the column with the header.
+func (ps *ParsedSheet) GetString(r int, header string) (string, error) {
+	c, err := ps.ColumnIndex(header)
+	if err != nil {
+		return "", err
+	}
+	return ps.ParsedString(r, c)
+}
+
+// GetFloat parses the row's cell in the column with the header as a float64.
+func (ps *ParsedSheet) GetFloat(r int, header string) (float64, error) {
+	c, err := ps.ColumnIndex(header)
+	if err != nil {
+		return 0, err
+	}
+	return ps.ParsedFloat(r, c)
+}
+
+// GetInt parses the row's cell in the column with the header as an int.
+func (ps *ParsedSheet) GetInt(r int, header string) (int, error) {
+	c, err := ps.ColumnIndex(header)
+	if err != nil {
+		return 0, err
+	}
+	return ps.ParsedInt(r, c)
+}
+
+// GetTime parses the row's cell in the column with the header as a time.Time.
+func (ps *ParsedSheet) GetTime(r int, header string) (time.Time, error) {
+	c, err := ps.ColumnIndex(header)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ps.ParsedTime(r, c)
+}
+
+// columnIndices returns the index of the column of every header.
+func (ps *ParsedSheet) columnIndices(headers []string) ([]int, error) {
+	indices := make([]int, 0, len(headers))
+	seen := make(map[string]bool)
+	for _, header := range headers {
+		if seen[header] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateHeader, header)
+		}
+		seen[header] = true
+		c, err := ps.ColumnIndex(header)
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, c)
+	}
+	return indices, nil
+}
+
+// arrangeColumns rebuilds every format of the data from the columns with the given indices in order.
+func (ps *ParsedSheet) arrangeColumns(indices []int) {
+	arrangeStrings := func(data [][]string) [][]string {
+		arranged := make([][]string, 0, len(data))
+		for _, row := range data {
+			newRow := make([]string, 0, len(indices))
+			for _, c := range indices {
+				newRow = append(newRow, row[c])
+			}
+			arranged = append(arranged, newRow)
+		}
+		return arranged
+	}
+	colOrigins := make([]int, 0, len(indices))
+	for _, c := range indices {
+		colOrigins = append(colOrigins, ps.ColOrigin(c))
+	}
+	ps.ColOrigins = colOrigins
+	ps.Original = arrangeStrings(ps.Original)
+	ps.DecimalFormat = arrangeStrings(ps.DecimalFormat)
+	if ps.Cells != nil {
+		cells := make([][]Cell, 0, len(ps.Cells))
+		for _, row := range ps.Cells {
+			cellRow := make([]Cell, 0, len(indices))
+			for _, c := range indices {
+				cellRow = append(cellRow, row[c])
+			}
+			cells = append(cells, cellRow)
+		}
+		ps.Cells = cells
+	}
+}
+
+// SelectColumns keeps only the columns with the given headers in the given order.
+//	The data is unchanged when any header is unknown or given more than once.
+func (ps *ParsedSheet) SelectColumns(headers ...string) error {
+	indices, err := ps.columnIndices(headers)
+	if err != nil {
+		return err
+	}
+	ps.arrangeColumns(indices)
+	return nil
+}
+
+// ReorderColumns moves the columns with the given headers to the left of the data in the given order.
+// The remaining columns keep their order to the right.
+//	The data is unchanged when any header is unknown or given more than once.
+func (ps *ParsedSheet) ReorderColumns(headers ...string) error {
+	indices, err := ps.columnIndices(headers)
+	if err != nil {
+		return err
+	}
+	moved := make(map[int]bool)
+	for _, c := range indices {
+		moved[c] = true
+	}
+	if len(ps.Original) > 0 {
+		for c := range ps.Original[0] {
+			if !moved[c] {
+				indices = append(indices, c)
+			}
+		}
+	}
+	ps.arrangeColumns(indices)
+	return nil
+}
+
+// uniqueHeaderErr returns ErrDuplicateHeader naming the first header found more than once.
+func uniqueHeaderErr(header []string) error {
+	seen := make(map[string]bool)
+	for _, h := range header {
+		if seen[h] {
+			return fmt.Errorf("%w: %s", ErrDuplicateHeader, h)
+		}
+		seen[h] = true
+	}
+	return nil
+}
+
+// RenameColumns renames the columns whose header is a key of names to its value.
+// Headers may be swapped but ErrDuplicateHeader is returned when a new name would be
+// the header of more than one column.
+//	The data is unchanged when any header is unknown or duplicated.
+func (ps *ParsedSheet) RenameColumns(names map[string]string) error {
+	renames := make(map[int]string)
+	for header, name := range names {
+		c, err := ps.ColumnIndex(header)
+		if err != nil {
+			return err
+		}
+		renames[c] = name
+	}
+	counts := make(map[string]int)
+	for c, header := range ps.Original[0] {
+		if name, ok := renames[c]; ok {
+			header = name
+		}
+		counts[header]++
+	}
+	for _, name := range renames {
+		if counts[name] > 1 {
+			return fmt.Errorf("%w: %s", ErrDuplicateHeader, name)
+		}
+	}
+	for c, name := range renames {
+		ps.Original[0][c] = name
+		ps.DecimalFormat[0][c] = name
+		if ps.Cells != nil {
+			ps.Cells[0][c] = textCell(name)
+		}
+	}
+	return nil
+}
+
+// derivedCell returns the cell of a value computed by AddColumn.
+//	Values with a decimal format which is a number are number cells.
+func derivedCell(original, decimal string) Cell {
+	if _, err := strconv.ParseFloat(decimal, 64); err == nil {
+		return Cell{Type: CellTypeNumber, Raw: decimal, NumFmtCode: builtInNumFmtCodes[0]}
+	}
+	return textCell(original)
+}
+
+// AddColumn inserts a new column with the header name at the position, moving the columns
+// at and after the position to the right. Position len(ps.Original[0]) appends the column.
+//	mapper is called once per data row and returns the cell's original and decimal formatted values.
+//	The new column has no worksheet origin, see CellAddress.
+func (ps *ParsedSheet) AddColumn(name string, position int, mapper func(Row) (original, decimal string)) error {
+	if len(ps.Original) == 0 || position < 0 || position > len(ps.Original[0]) {
+		return ErrInvalidIndices
+	}
+	originals := make([]string, len(ps.Original))
+	decimals := make([]string, len(ps.Original))
+	originals[0], decimals[0] = name, name
+	for r := 1; r < len(ps.Original); r++ {
+		originals[r], decimals[r] = mapper(ps.row(r))
+	}
+	insert := func(row []string, v string) []string {
+		newRow := make([]string, 0, len(row)+1)
+		newRow = append(newRow, row[:position]...)
+		newRow = append(newRow, v)
+		return append(newRow, row[position:]...)
+	}
+	for r := range ps.Original {
+		ps.Original[r] = insert(ps.Original[r], originals[r])
+		ps.DecimalFormat[r] = insert(ps.DecimalFormat[r], decimals[r])
+	}
+	if ps.Cells != nil {
+		for r := range ps.Cells {
+			cell := textCell(name)
+			if r > 0 {
+				cell = derivedCell(originals[r], decimals[r])
+			}
+			cellRow := make([]Cell, 0, len(ps.Cells[r])+1)
+			cellRow = append(cellRow, ps.Cells[r][:position]...)
+			cellRow = append(cellRow, cell)
+			ps.Cells[r] = append(cellRow, ps.Cells[r][position:]...)
+		}
+	}
+	colOrigins := ps.colOrigins()
+	ps.ColOrigins = append(append(append([]int(nil), colOrigins[:position]...), 0), colOrigins[position:]...)
+	return nil
+}
@@ -0,0 +1,177 @@
+package parse
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// ErrNoDiffKeys is returned when a diff is attempted without any key headers.
+var ErrNoDiffKeys = errors.New("sheetParse: diff requires at least one key header")
+
+// CellChange is a cell whose value differs between the old and new sheet of a diff.
+type CellChange struct {
+	Header string
+	// OldValue and NewValue are the originally formatted values.
+	OldValue string
+	NewValue string
+	// OldAddress and NewAddress are the worksheet addresses of the cells.
+	// Empty when the cell was not read from a worksheet.
+	OldAddress string
+	NewAddress string
+}
+
+// RowChange is a row found in both sheets of a diff with at least one changed cell.
+type RowChange struct {
+	Key []string
+	// OldRow and NewRow are the indices of the row in each sheet.
+	OldRow  int
+	NewRow  int
+	Changes []CellChange
+}
+
+// SheetDiff holds the differences between two versions of a sheet matched by key columns.
+type SheetDiff struct {
+	Old        *ParsedSheet
+	New        *ParsedSheet
+	KeyHeaders []string
+	// Added holds the indices of the rows of New whose key is not found in Old.
+	Added []int
+	// Removed holds the indices of the rows of Old whose key is not found in New.
+	Removed []int
+	Changed []RowChange
+	// AddedHeaders and RemovedHeaders hold the headers found in only one of the sheets.
+	// Their cells are not compared.
+	AddedHeaders   []string
+	RemovedHeaders []string
+}
+
+// HasChanges returns whether the sheets differ.
+func (d SheetDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0 ||
+		len(d.AddedHeaders) > 0 || len(d.RemovedHeaders) > 0
+}
+
+// valuesEqual returns whether two decimal formatted values are equal, comparing
+// numbers within the tolerance.
+func valuesEqual(a, b string, tolerance float64) bool {
+	if a == b {
+		return true
+	}
+	af, aErr := strconv.ParseFloat(a, 64)
+	bf, bErr := strconv.ParseFloat(b, 64)
+	if aErr != nil || bErr != nil {
+		return false
+	}
+	return math.Abs(af-bf) <= tolerance
+}
+
+// occurrenceKeys returns the key of every data row numbered by its occurrence so the
+// n-th row with a repeated key is matched to the n-th row of the other sheet.
+func occurrenceKeys(ps *ParsedSheet, keyCols []int) ([]string, map[string]int) {
+	counts := make(map[string]int)
+	keys := make([]string, len(ps.DecimalFormat))
+	rows := make(map[string]int)
+	for r := 1; r < len(ps.DecimalFormat); r++ {
+		key := joinKey(ps.DecimalFormat[r], keyCols)
+		counts[key]++
+		keys[r] = key + "\x00" + strconv.Itoa(counts[key])
+		rows[keys[r]] = r
+	}
+	return keys, rows
+}
+
+// Diff returns the rows added, removed and changed between two versions of a sheet
+// matched on the decimal formatted values of the key columns.
+//	Numbers must be equal, see DiffWithTolerance.
+func Diff(old, new *ParsedSheet, keyHeaders ...string) (SheetDiff, error) {
+	return DiffWithTolerance(old, new, 0, keyHeaders...)
+}
+
+// DiffWithTolerance returns the differences in the same way as Diff while treating
+// numbers within the tolerance of each other as equal.
+//	Cells are compared by header so columns may be reordered between the versions.
+//	ErrDuplicateHeader is returned when either sheet repeats a header, see RemoveDuplicateColumnsFromRow.
+//	Rows with a repeated key are matched in order of occurrence.
+func DiffWithTolerance(old, new *ParsedSheet, tolerance float64, keyHeaders ...string) (SheetDiff, error) {
+	d := SheetDiff{Old: old, New: new, KeyHeaders: keyHeaders}
+	if len(keyHeaders) == 0 {
+		return d, ErrNoDiffKeys
+	}
+	if len(old.Original) == 0 || len(new.Original) == 0 {
+		return d, ErrInvalidData
+	}
+	for _, ps := range []*ParsedSheet{old, new} {
+		if err := uniqueHeaderErr(ps.Original[0]); err != nil {
+			return d, err
+		}
+	}
+	oldKeyCols, err := old.columnIndices(keyHeaders)
+	if err != nil {
+		return d, err
+	}
+	newKeyCols, err := new.columnIndices(keyHeaders)
+	if err != nil {
+		return d, err
+	}
+
+	isKey := make(map[string]bool)
+	for _, h := range keyHeaders {
+		isKey[h] = true
+	}
+	// compared holds the old and new index of every non key column found in both sheets.
+	compared := make([][2]int, 0)
+	for oc, h := range old.Original[0] {
+		nc, err := new.ColumnIndex(h)
+		if err != nil {
+			d.RemovedHeaders = append(d.RemovedHeaders, h)
+			continue
+		}
+		if !isKey[h] {
+			compared = append(compared, [2]int{oc, nc})
+		}
+	}
+	for _, h := range new.Original[0] {
+		if _, err := old.ColumnIndex(h); err != nil {
+			d.AddedHeaders = append(d.AddedHeaders, h)
+		}
+	}
+
+	oldKeys, oldRows := occurrenceKeys(old, oldKeyCols)
+	newKeys, newRows := occurrenceKeys(new, newKeyCols)
+	for or := 1; or < len(old.DecimalFormat); or++ {
+		nr, ok := newRows[oldKeys[or]]
+		if !ok {
+			d.Removed = append(d.Removed, or)
+			continue
+		}
+		change := RowChange{OldRow: or, NewRow: nr}
+		for _, cols := range compared {
+			oc, nc := cols[0], cols[1]
+			if valuesEqual(old.DecimalFormat[or][oc], new.DecimalFormat[nr][nc], tolerance) {
+				continue
+			}
+			oldAddr, _ := old.CellAddress(or, oc)
+			newAddr, _ := new.CellAddress(nr, nc)
+			change.Changes = append(change.Changes, CellChange{
+				Header:     old.Original[0][oc],
+				OldValue:   old.Original[or][oc],
+				NewValue:   new.Original[nr][nc],
+				OldAddress: oldAddr,
+				NewAddress: newAddr,
+			})
+		}
+		if len(change.Changes) > 0 {
+			for _, c := range newKeyCols {
+				change.Key = append(change.Key, new.Original[nr][c])
+			}
+			d.Changed = append(d.Changed, change)
+		}
+	}
+	for nr := 1; nr < len(new.DecimalFormat); nr++ {
+		if _, ok := oldRows[newKeys[nr]]; !ok {
+			d.Added = append(d.Added, nr)
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,53 @@
+package parse
+
+import (
+	"strings"
+)
+
+// firstNonBlank returns the first cell of the row which is not blank.
+func firstNonBlank(row []string) string {
+	for _, s := range row {
+		if strings.TrimSpace(s) != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// applyFooterOptions ends the data before any footer rows found with the options.
+//	The header row is never removed.
+func (ps *ParsedSheet) applyFooterOptions(opts ParseOptions) {
+	end := len(ps.Original)
+	if opts.StopAtRow != nil {
+		for r := 1; r < end; r++ {
+			if opts.StopAtRow(ps.Original[r]) {
+				end = r
+				break
+			}
+		}
+	}
+	if opts.TrailingRowPattern != nil {
+		for end > 1 {
+			first := firstNonBlank(ps.Original[end-1])
+			if first != "" && !opts.TrailingRowPattern.MatchString(first) {
+				break
+			}
+			end--
+		}
+	}
+	ps.truncateRows(end)
+}
+
+// truncateRows keeps the first n rows of every format of the data.
+func (ps *ParsedSheet) truncateRows(n int) {
+	if n >= len(ps.Original) {
+		return
+	}
+	ps.Original = ps.Original[:n]
+	ps.DecimalFormat = ps.DecimalFormat[:n]
+	if ps.Cells != nil {
+		ps.Cells = ps.Cells[:n]
+	}
+	ps.RowOrigins = ps.rowOrigins()[:n]
+	ps.HiddenRows = keepOrigins(ps.HiddenRows, ps.RowOrigins)
+}
@@ -0,0 +1,137 @@
+package parse
+
+import (
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"strconv"
+)
+
+// errorValues holds the error values a formula may result in.
+var errorValues = map[string]bool{
+	"#DIV/0!": true,
+	"#N/A":    true,
+	"#NAME?":  true,
+	"#NULL!":  true,
+	"#NUM!":   true,
+	"#REF!":   true,
+	"#VALUE!": true,
+}
+
+// cellPosition is the zero based worksheet row and column of a cell.
+type cellPosition struct {
+	row, col int
+}
+
+// evaluateFormulas calculates every formula of the sheet which has no cached value, keyed by
+// its position. Formulas which can not be calculated are left out.
+//	Formulas are evaluated before the rows are shaped so that rows and headers made only of
+//	uncached formulas are not mistaken for blank cells.
+func evaluateFormulas(f *excelize.File, sheet string) (map[cellPosition]string, error) {
+	wsPath, err := loadWorksheet(f, sheet)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[cellPosition]string)
+	for _, row := range f.Sheet[wsPath].SheetData.Row {
+		for _, c := range row.C {
+			if c.F == nil || c.V != "" {
+				continue
+			}
+			col, r, err := excelize.CellNameToCoordinates(c.R)
+			if err != nil {
+				continue
+			}
+			result, err := f.CalcCellValue(sheet, c.R)
+			if err != nil {
+				if !errorValues[err.Error()] {
+					continue
+				}
+				result = err.Error()
+			}
+			if result != "" {
+				values[cellPosition{row: r - 1, col: col - 1}] = result
+			}
+		}
+	}
+	return values, nil
+}
+
+// withEvaluated returns a copy of the rows with the evaluated values in place,
+// adding the rows and columns needed to hold them.
+func withEvaluated(rows [][]string, values map[cellPosition]string) [][]string {
+	copied := make([][]string, len(rows))
+	for r := range rows {
+		copied[r] = append([]string(nil), rows[r]...)
+	}
+	for pos, v := range values {
+		for len(copied) <= pos.row {
+			copied = append(copied, []string{})
+		}
+		for len(copied[pos.row]) <= pos.col {
+			copied[pos.row] = append(copied[pos.row], "")
+		}
+		copied[pos.row][pos.col] = v
+	}
+	return copied
+}
+
+// applyFormulaOptions resolves the formula cells of the sheet and types the evaluated ones.
+func (ps *ParsedSheet) applyFormulaOptions(f *excelize.File, opts ParseOptions, evaluated map[cellPosition]string) error {
+	if !opts.Formulas && !opts.Evaluate {
+		return nil
+	}
+	for r := range ps.Cells {
+		for c := range ps.Cells[r] {
+			cell := &ps.Cells[r][c]
+			if !cell.IsFormula() {
+				continue
+			}
+			if opts.Formulas {
+				axis, err := ps.CellAddress(r, c)
+				if err != nil {
+					return err
+				}
+				formula, err := f.GetCellFormula(ps.Name, axis)
+				if err != nil {
+					return err
+				}
+				cell.Formula = formula
+			}
+			if result, ok := evaluated[cellPosition{row: ps.RowOrigin(r) - 1, col: ps.ColOrigin(c) - 1}]; ok {
+				setEvaluatedCell(cell, result)
+			}
+		}
+	}
+	return nil
+}
+
+// setEvaluatedCell types a formula cell from its calculated result.
+func setEvaluatedCell(cell *Cell, result string) {
+	switch {
+	case errorValues[result]:
+		cell.Type, cell.Error = CellTypeError, result
+	default:
+		if _, err := strconv.ParseFloat(result, 64); err == nil {
+			cell.Type = CellTypeNumber
+		} else {
+			cell.Type = CellTypeString
+		}
+	}
+	cell.Raw = result
+}
+
+// FormulaColumns returns the indices of the columns which contain a formula below the header row.
+func (ps *ParsedSheet) FormulaColumns() []int {
+	columns := make([]int, 0)
+	if len(ps.Cells) == 0 {
+		return columns
+	}
+	for c := range ps.Cells[0] {
+		for r := 1; r < len(ps.Cells); r++ {
+			if c < len(ps.Cells[r]) && ps.Cells[r][c].IsFormula() {
+				columns = append(columns, c)
+				break
+			}
+		}
+	}
+	return columns
+}
@@ -0,0 +1,40 @@
+package parse
+
+import (
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"strconv"
+	"strings"
+)
+
+// GeneratedHeaderPrefix is the start of the name given to columns with a blank header.
+const GeneratedHeaderPrefix = "Column_"
+
+// autoNameColumns names every blank header after its worksheet column e.g. "Column_F".
+//	A name already used by another header is numbered e.g. "Column_F_2".
+func (ps *ParsedSheet) autoNameColumns() {
+	ps.GeneratedHeaders = make([]string, 0)
+	if len(ps.Original) == 0 {
+		return
+	}
+	used := make(map[string]bool)
+	for _, header := range ps.Original[0] {
+		used[header] = true
+	}
+	for c, header := range ps.Original[0] {
+		if strings.TrimSpace(header) != "" {
+			continue
+		}
+		colName, err := excelize.ColumnNumberToName(ps.ColOrigin(c))
+		if err != nil {
+			continue
+		}
+		name := GeneratedHeaderPrefix + colName
+		for n := 2; used[name]; n++ {
+			name = GeneratedHeaderPrefix + colName + "_" + strconv.Itoa(n)
+		}
+		used[name] = true
+		ps.Original[0][c] = name
+		ps.DecimalFormat[0][c] = name
+		ps.GeneratedHeaders = append(ps.GeneratedHeaders, name)
+	}
+}
@@ -0,0 +1,201 @@
+package parse
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNoJoinKeys is returned when a join is attempted without any key headers.
+var ErrNoJoinKeys = errors.New("sheetParse: join requires at least one key header")
+
+// JoinType is the kind of join performed by Join.
+type JoinType int
+
+const (
+	// InnerJoin keeps the rows with a key found in both sheets.
+	InnerJoin JoinType = iota
+	// LeftJoin keeps every row of the left sheet with blank values when the key is not found in the right sheet.
+	LeftJoin
+	// FullOuterJoin keeps every row of both sheets.
+	FullOuterJoin
+)
+
+// DefaultLeftSuffix and DefaultRightSuffix are added to the clashing column headers of a join
+// when JoinOptions does not set either suffix.
+const (
+	DefaultLeftSuffix  = "_x"
+	DefaultRightSuffix = "_y"
+)
+
+// JoinOptions describes how Join matches the rows of two sheets.
+type JoinOptions struct {
+	Type JoinType
+	// Keys are the headers of the columns whose decimal formatted values must be equal
+	// for two rows to match. Both sheets must have every key.
+	Keys []string
+	// LeftSuffix and RightSuffix are added to the headers of the non key columns found in both sheets.
+	LeftSuffix  string
+	RightSuffix string
+}
+
+// suffixes returns the suffixes of the options or the default suffixes.
+func (opts JoinOptions) suffixes() (string, string) {
+	if opts.LeftSuffix == "" && opts.RightSuffix == "" {
+		return DefaultLeftSuffix, DefaultRightSuffix
+	}
+	return opts.LeftSuffix, opts.RightSuffix
+}
+
+// joinKey returns the decimal formatted values of the key columns of a row joined as a map key.
+func joinKey(row []string, keyIdxs []int) string {
+	values := make([]string, 0, len(keyIdxs))
+	for _, c := range keyIdxs {
+		values = append(values, row[c])
+	}
+	return strings.Join(values, "\x00")
+}
+
+// Join returns a new ParsedSheet of the rows of left and right matched on the key columns.
+// The result has every column of left followed by the non key columns of right.
+// ErrDuplicateHeader is returned when the headers of the result would not be unique.
+//	Rows of left are kept in order, each followed by its matches in the order of right.
+//	A full outer join then adds the unmatched rows of right.
+//	The result has no worksheet origins or cells as its values come from both sheets.
+func Join(left, right *ParsedSheet, opts JoinOptions) (*ParsedSheet, error) {
+	if len(opts.Keys) == 0 {
+		return nil, ErrNoJoinKeys
+	}
+	if len(left.Original) == 0 || len(right.Original) == 0 {
+		return nil, ErrInvalidData
+	}
+	leftKeys, err := left.columnIndices(opts.Keys)
+	if err != nil {
+		return nil, err
+	}
+	rightKeys, err := right.columnIndices(opts.Keys)
+	if err != nil {
+		return nil, err
+	}
+	isRightKey := make(map[int]bool)
+	for _, c := range rightKeys {
+		isRightKey[c] = true
+	}
+	rightCols := make([]int, 0, len(right.Original[0]))
+	for c := range right.Original[0] {
+		if !isRightKey[c] {
+			rightCols = append(rightCols, c)
+		}
+	}
+
+	isKey := make(map[string]bool)
+	for _, key := range opts.Keys {
+		isKey[key] = true
+	}
+	leftHeaders := make(map[string]bool)
+	for _, h := range left.Original[0] {
+		leftHeaders[h] = true
+	}
+	rightHeaders := make(map[string]bool)
+	for _, c := range rightCols {
+		rightHeaders[right.Original[0][c]] = true
+	}
+	leftSuffix, rightSuffix := opts.suffixes()
+	header := make([]string, 0, len(left.Original[0])+len(rightCols))
+	for _, h := range left.Original[0] {
+		if !isKey[h] && rightHeaders[h] {
+			h += leftSuffix
+		}
+		header = append(header, h)
+	}
+	for _, c := range rightCols {
+		h := right.Original[0][c]
+		if leftHeaders[h] {
+			h += rightSuffix
+		}
+		header = append(header, h)
+	}
+	// Suffixed headers may clash with other headers or with each other when the suffixes are equal.
+	if err := uniqueHeaderErr(header); err != nil {
+		return nil, err
+	}
+
+	matches := make(map[string][]int)
+	for r := 1; r < len(right.DecimalFormat); r++ {
+		key := joinKey(right.DecimalFormat[r], rightKeys)
+		matches[key] = append(matches[key], r)
+	}
+	joined := &ParsedSheet{
+		Original:      [][]string{header},
+		DecimalFormat: [][]string{append([]string(nil), header...)},
+		Name:          left.Name,
+		Path:          left.Path,
+		FileName:      left.FileName,
+		Date1904:      left.Date1904,
+	}
+	// addRow adds the joined row of a left and right row where zero is a missing row.
+	addRow := func(l, r int) {
+		for _, format := range []struct {
+			dst               *[][]string
+			leftRow, rightRow [][]string
+		}{
+			{&joined.Original, left.Original, right.Original},
+			{&joined.DecimalFormat, left.DecimalFormat, right.DecimalFormat},
+		} {
+			row := make([]string, 0, len(header))
+			if l > 0 {
+				row = append(row, format.leftRow[l]...)
+			} else {
+				row = append(row, make([]string, len(left.Original[0]))...)
+				for i, c := range leftKeys {
+					row[c] = format.rightRow[r][rightKeys[i]]
+				}
+			}
+			for _, c := range rightCols {
+				if r > 0 {
+					row = append(row, format.rightRow[r][c])
+				} else {
+					row = append(row, "")
+				}
+			}
+			*format.dst = append(*format.dst, row)
+		}
+	}
+	matched := make(map[int]bool)
+	for l := 1; l < len(left.DecimalFormat); l++ {
+		rows := matches[joinKey(left.DecimalFormat[l], leftKeys)]
+		if len(rows) == 0 && opts.Type != InnerJoin {
+			addRow(l, 0)
+		}
+		for _, r := range rows {
+			matched[r] = true
+			addRow(l, r)
+		}
+	}
+	if opts.Type == FullOuterJoin {
+		for r := 1; r < len(right.DecimalFormat); r++ {
+			if !matched[r] {
+				addRow(0, r)
+			}
+		}
+	}
+	joined.RowOrigins = make([]int, len(joined.Original))
+	joined.ColOrigins = make([]int, len(header))
+	return joined, nil
+}
+
+// ToParsedSheet returns the aggregation as a ParsedSheet with its header as the first row
+// so it can be used with the operations of a ParsedSheet such as Join.
+//	The result has no worksheet origins or cells as its rows may come from several sheets.
+func (ap *AggregatedParse) ToParsedSheet() *ParsedSheet {
+	ps := &ParsedSheet{
+		Original:      [][]string{append([]string(nil), ap.Header...)},
+		DecimalFormat: [][]string{append([]string(nil), ap.Header...)},
+	}
+	for _, item := range ap.Items {
+		ps.Original = append(ps.Original, append([]string(nil), item.OriginalFormat...))
+		ps.DecimalFormat = append(ps.DecimalFormat, append([]string(nil), item.DecimalFormat...))
+	}
+	ps.RowOrigins = make([]int, len(ps.Original))
+	ps.ColOrigins = make([]int, len(ap.Header))
+	return ps
+}
@@ -0,0 +1,227 @@
+package parse
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrFormattedNumber is returned when text can not be read as a formatted number.
+var ErrFormattedNumber = errors.New("sheetParse: text is not a formatted number")
+
+// NumberLocale describes the separators used when a number is stored as formatted text.
+type NumberLocale struct {
+	DecimalSeparator   rune
+	ThousandsSeparator rune
+}
+
+// LocaleUS reads numbers such as "$1,234.50".
+var LocaleUS = NumberLocale{DecimalSeparator: '.', ThousandsSeparator: ','}
+
+// LocaleEU reads numbers such as "1.234,56 €" or "1 234,56".
+var LocaleEU = NumberLocale{DecimalSeparator: ',', ThousandsSeparator: '.'}
+
+// isNumberSpace returns whether r is a space which may be used to group digits.
+func isNumberSpace(r rune) bool {
+	return unicode.IsSpace(r) || r == ' ' || r == ' '
+}
+
+// trimCurrencyCode removes a leading or trailing three letter ISO currency code e.g. USD.
+func trimCurrencyCode(s string) string {
+	isCode := func(code string) bool {
+		for _, r := range code {
+			if r < 'A' || r > 'Z' {
+				return false
+			}
+		}
+		return true
+	}
+	if len(s) > 3 && isCode(s[:3]) {
+		return s[3:]
+	}
+	if len(s) > 3 && isCode(s[len(s)-3:]) {
+		return s[:len(s)-3]
+	}
+	return s
+}
+
+// ParseFormattedNumber reads a number stored as formatted text using the locale's separators.
+// It handles currency symbols and codes, thousands separators, parentheses and trailing
+// minus negatives, percents and decimal commas.
+//	e.g. "$1,234.50", "(300.00)", "12.5%", "1.234,56 €"
+func ParseFormattedNumber(s string, loc NumberLocale) (float64, error) {
+	number, percent, err := normalizeFormattedNumber(s, loc)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, ErrFormattedNumber
+	}
+	if percent {
+		f /= 100
+	}
+	return f, nil
+}
+
+// ParseFormattedRat reads a number stored as formatted text in the same way as
+// ParseFormattedNumber without rounding it to a float64.
+func ParseFormattedRat(s string, loc NumberLocale) (*big.Rat, error) {
+	number, percent, err := normalizeFormattedNumber(s, loc)
+	if err != nil {
+		return nil, err
+	}
+	rat, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return nil, ErrFormattedNumber
+	}
+	if percent {
+		rat.Quo(rat, big.NewRat(100, 1))
+	}
+	return rat, nil
+}
+
+// normalizeFormattedNumber strips the formatting of a number stored as text returning
+// a number which can be read by strconv along with whether the number was a percent.
+func normalizeFormattedNumber(s string, loc NumberLocale) (string, bool, error) {
+	s = strings.TrimFunc(s, isNumberSpace)
+	negative, percent := false, false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+	// Signs, symbols and codes may be written in any order around the digits e.g. "-$5" or "$-5".
+	for {
+		trimmed := strings.TrimFunc(s, isNumberSpace)
+		trimmed = trimCurrencyCode(trimmed)
+		trimmed = strings.TrimFunc(trimmed, func(r rune) bool {
+			return unicode.Is(unicode.Sc, r) || isNumberSpace(r)
+		})
+		switch {
+		case strings.HasPrefix(trimmed, "-"):
+			negative = !negative
+			trimmed = trimmed[1:]
+		case strings.HasSuffix(trimmed, "-"):
+			negative = !negative
+			trimmed = trimmed[:len(trimmed)-1]
+		case strings.HasPrefix(trimmed, "+"):
+			trimmed = trimmed[1:]
+		}
+		if strings.HasPrefix(trimmed, "%") || strings.HasSuffix(trimmed, "%") {
+			percent = true
+			trimmed = strings.Trim(trimmed, "%")
+		}
+		if trimmed == s {
+			break
+		}
+		s = trimmed
+	}
+
+	var b strings.Builder
+	if negative {
+		b.WriteRune('-')
+	}
+	seenDecimal, seenDigit := false, false
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			seenDigit = true
+			b.WriteRune(r)
+		case r == loc.DecimalSeparator && !seenDecimal:
+			seenDecimal = true
+			b.WriteRune('.')
+		case r == loc.ThousandsSeparator && !seenDecimal, isNumberSpace(r) && !seenDecimal:
+			continue
+		default:
+			return "", false, ErrFormattedNumber
+		}
+	}
+	if !seenDigit {
+		return "", false, ErrFormattedNumber
+	}
+	return b.String(), percent, nil
+}
+
+// numericCell returns whether the cell at the pair of indices is stored as a number
+// and whether its type is known, which it is not for sheets without Cells.
+func (ps *ParsedSheet) numericCell(r, c int) (numeric, known bool) {
+	if r >= len(ps.Cells) || c >= len(ps.Cells[r]) {
+		return false, false
+	}
+	t := ps.Cells[r][c].Type
+	return t == CellTypeNumber || t == CellTypeDate, true
+}
+
+// localeFirst returns whether the originally formatted text of a cell is read with the locale
+// before its decimal format, and whether the decimal format may be used when that fails.
+//	Cells stored as numbers always use their decimal format. Text cells, and cells of unknown type
+//	read with a locale other than LocaleUS, are read with the locale first.
+func (ps *ParsedSheet) localeFirst(r, c int, loc NumberLocale) (first, fallback bool) {
+	numeric, known := ps.numericCell(r, c)
+	switch {
+	case numeric:
+		return false, true
+	case known:
+		return true, false
+	}
+	return loc != LocaleUS, true
+}
+
+// ParsedFloatLocale attempts to parse the cell value as a float64.
+// Numeric cells use the decimal formatted string while text cells are read from the
+// originally formatted text with the locale's separators, see localeFirst.
+func (ps *ParsedSheet) ParsedFloatLocale(r, c int, loc NumberLocale) (float64, error) {
+	if err := ps.indexErr(r, c); err != nil {
+		return 0, err
+	}
+	if err := ps.cellErr(r, c); err != nil {
+		return 0, err
+	}
+	first, fallback := ps.localeFirst(r, c, loc)
+	if first {
+		f, err := ParseFormattedNumber(ps.Original[r][c], loc)
+		if err == nil || !fallback {
+			return f, err
+		}
+		return ps.ParsedFloat(r, c)
+	}
+	f, err := ps.ParsedFloat(r, c)
+	if err == nil {
+		return f, nil
+	}
+	return ParseFormattedNumber(ps.Original[r][c], loc)
+}
+
+// ParsedIntLocale attempts to parse the cell value as an int in the same way as ParsedFloatLocale.
+func (ps *ParsedSheet) ParsedIntLocale(r, c int, loc NumberLocale) (int, error) {
+	f, err := ps.ParsedFloatLocale(r, c, loc)
+	if err != nil {
+		return 0, err
+	}
+	return int(f), nil
+}
+
+// ParsedRatLocale attempts to parse the cell value as an exact *big.Rat in the same way as ParsedFloatLocale.
+func (ps *ParsedSheet) ParsedRatLocale(r, c int, loc NumberLocale) (*big.Rat, error) {
+	if err := ps.indexErr(r, c); err != nil {
+		return nil, err
+	}
+	if err := ps.cellErr(r, c); err != nil {
+		return nil, err
+	}
+	first, fallback := ps.localeFirst(r, c, loc)
+	if first {
+		rat, err := ParseFormattedRat(ps.Original[r][c], loc)
+		if err == nil || !fallback {
+			return rat, err
+		}
+		return ps.ParsedRat(r, c)
+	}
+	rat, err := ps.ParsedRat(r, c)
+	if err == nil {
+		return rat, nil
+	}
+	return ParseFormattedRat(ps.Original[r][c], loc)
+}
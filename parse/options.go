@@ -0,0 +1,61 @@
+package parse
+
+import (
+	"regexp"
+)
+
+// ParseOptions controls the optional information read when parsing a sheet.
+// The zero value parses a sheet in the same way as MakeParsedSheet.
+type ParseOptions struct {
+	// Formulas resolves the full formula text of every formula cell,
+	// including cells sharing the formula of another cell.
+	Formulas bool
+	// Evaluate calculates formula cells which have no cached value with excelize's CalcCellValue.
+	// Workbooks saved by tools other than Excel often have no cached values.
+	Evaluate bool
+	// Comments loads the text and author of every cell comment into ParsedSheet.Comments.
+	Comments bool
+	// Hyperlinks loads the target of every hyperlink into ParsedSheet.Hyperlinks.
+	Hyperlinks bool
+	// Validations loads the values of every data validation drop down list into ParsedSheet.Validations.
+	Validations bool
+	// SkipHiddenRows removes hidden and filtered out rows below the header.
+	SkipHiddenRows bool
+	// SkipHiddenColumns removes hidden columns.
+	SkipHiddenColumns bool
+	// SkipHiddenSheets skips hidden and very hidden sheets when parsing a file.
+	SkipHiddenSheets bool
+
+	// StopAtBlankRow ends the data at the first blank row e.g. before notes below a table.
+	StopAtBlankRow bool
+	// StopAtRow ends the data before the first row below the header for which it returns true.
+	StopAtRow func(row []string) bool
+	// TrailingRowPattern drops trailing rows whose first non blank cell matches the pattern,
+	// along with any blank rows between them e.g. TotalRowPattern.
+	TrailingRowPattern *regexp.Regexp
+
+	// AutoNameColumns keeps every column up to the last used column instead of
+	// stopping at the first blank header. Blank headers are named after their
+	// column e.g. "Column_F" and recorded in ParsedSheet.GeneratedHeaders.
+	AutoNameColumns bool
+}
+
+// TotalRowPattern matches the first cell of total rows e.g. "Total", "Grand Total" or "TOTALS:".
+var TotalRowPattern = regexp.MustCompile(`(?i)^\s*(grand\s+|sub\s*)?totals?\b`)
+
+// shape returns the function used to re-dimension the cells of a sheet.
+func (opts ParseOptions) shape() func([][]string) [][]string {
+	if !opts.AutoNameColumns {
+		if opts.StopAtBlankRow {
+			return shapeCells2
+		}
+		return shapeCells
+	}
+	return func(cells [][]string) [][]string {
+		cells = shapeUsedWidth(cells)
+		if opts.StopAtBlankRow {
+			cells = endAtBlankRow(cells)
+		}
+		return cells
+	}
+}
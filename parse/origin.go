@@ -0,0 +1,87 @@
+package parse
+
+import (
+	"errors"
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+// ErrNoCellOrigin is returned when the cell at a pair of indices was not read from a worksheet
+// e.g. a column added by ApplyPrefixColumn.
+var ErrNoCellOrigin = errors.New("sheetParse: cell has no worksheet origin")
+
+// offsetOrigins returns the worksheet numbers of n rows or columns starting after the offset.
+func offsetOrigins(offset, n int) []int {
+	origins := make([]int, n)
+	for i := range origins {
+		origins[i] = offset + i + 1
+	}
+	return origins
+}
+
+// rowOrigins returns the worksheet row number of every row of the data.
+func (ps *ParsedSheet) rowOrigins() []int {
+	if ps.RowOrigins != nil {
+		return ps.RowOrigins
+	}
+	return offsetOrigins(ps.RowOffset, len(ps.Original))
+}
+
+// colOrigins returns the worksheet column number of every column of the data.
+func (ps *ParsedSheet) colOrigins() []int {
+	if ps.ColOrigins != nil {
+		return ps.ColOrigins
+	}
+	if len(ps.Original) == 0 {
+		return []int{}
+	}
+	return offsetOrigins(ps.ColOffset, len(ps.Original[0]))
+}
+
+// keepOrigins returns the worksheet numbers which are still within the origins.
+func keepOrigins(numbers, origins []int) []int {
+	present := make(map[int]bool, len(origins))
+	for _, o := range origins {
+		present[o] = true
+	}
+	kept := make([]int, 0, len(numbers))
+	for _, n := range numbers {
+		if present[n] {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// RowOrigin returns the worksheet row number of the row with the given index
+// or zero when the row was not read from a worksheet.
+func (ps *ParsedSheet) RowOrigin(r int) int {
+	origins := ps.rowOrigins()
+	if r < 0 || r >= len(origins) {
+		return 0
+	}
+	return origins[r]
+}
+
+// ColOrigin returns the worksheet column number of the column with the given index
+// or zero when the column was not read from a worksheet.
+func (ps *ParsedSheet) ColOrigin(c int) int {
+	origins := ps.colOrigins()
+	if c < 0 || c >= len(origins) {
+		return 0
+	}
+	return origins[c]
+}
+
+// CellAddress returns the worksheet address e.g. "B2" of the cell at the pair of indices.
+// The address is that of the cell the value was read from, regardless of the rows and
+// columns removed or added since the sheet was parsed.
+func (ps *ParsedSheet) CellAddress(r, c int) (string, error) {
+	if err := ps.indexErr(r, c); err != nil {
+		return "", err
+	}
+	row, col := ps.RowOrigin(r), ps.ColOrigin(c)
+	if row == 0 || col == 0 {
+		return "", ErrNoCellOrigin
+	}
+	return excelize.CoordinatesToCellName(col, row)
+}
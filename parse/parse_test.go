@@ -1,11 +1,14 @@
 package parse
 
 import (
+	"errors"
 	"fmt"
 	"github.com/360EntSecGroup-Skylar/excelize/v2"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"testing"
+	"time"
 )
 
 var dataFilePath = filepath.Join("data", "data.xlsx")
@@ -135,4 +138,1031 @@ func TestParsedSheet_ApplyPrefixColumn(t *testing.T) {
 	}
 	ps.ApplyPrefixColumn("ROW_NUMBER", rowMapper())
 	fmt.Println(ps.Original[0])
-}
\ No newline at end of file
+	rows := len(ps.Original) - 1
+	if ps.Original[rows][0] != strconv.Itoa(rows) || ps.DecimalFormat[rows][0] != strconv.Itoa(2*rows) {
+		t.Error("mapper should be called once per row for each format, got", ps.Original[rows][0], ps.DecimalFormat[rows][0])
+	}
+}
+func TestParseTimeText(t *testing.T) {
+	tm, err := ParseTimeText("03/01/2020 13:45", DefaultTimeLayouts, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tm.Equal(time.Date(2020, 3, 1, 13, 45, 0, 0, time.UTC)) {
+		t.Error("unexpected parsed time", tm)
+	}
+	if _, err := ParseTimeText("not a date", DefaultTimeLayouts, time.UTC); err != ErrTimeText {
+		t.Error("expected ErrTimeText, got", err)
+	}
+}
+
+func TestDate1904(t *testing.T) {
+	f := excelize.NewFile()
+	f.WorkBook.WorkbookPr.Date1904 = true
+	sheet := f.GetSheetName(0)
+	_ = f.SetCellValue(sheet, "A1", "DATE")
+	_ = f.SetCellValue(sheet, "A2", 42429)
+	ps, err := MakeParsedSheet(f, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ps.Date1904 {
+		t.Fatal("parsed sheet should use the 1904 date system")
+	}
+	want := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	if tm, err := ps.ParsedTime(1, 0); err != nil || !tm.Equal(want) {
+		t.Error("1904 serial 42429 should be", want, "is", tm, err)
+	}
+	_ = f.SetCellValue(sheet, "A3", "2020-03-01")
+	ps, _ = MakeParsedSheet(f, sheet)
+	if _, err := ps.ParsedTime(2, 0); err == nil {
+		t.Error("ParsedTime should not parse text cells")
+	}
+	if tm, err := ps.ParsedTimeIn(2, 0, DefaultTimeLayouts, time.UTC); err != nil || !tm.Equal(want) {
+		t.Error("ParsedTimeIn should parse the text cell as", want, "is", tm, err)
+	}
+	if serial := TimeToExcelDate(want, true); serial != 42429 {
+		t.Error("1904 serial should be 42429, is", serial)
+	}
+	if serial := TimeToExcelDate(want, false); serial != 43891 {
+		t.Error("1900 serial should be 43891, is", serial)
+	}
+}
+
+func TestParseFormattedNumber(t *testing.T) {
+	cases := []struct {
+		s    string
+		loc  NumberLocale
+		want float64
+	}{
+		{"$1,234.50", LocaleUS, 1234.5},
+		{"(300.00)", LocaleUS, -300},
+		{"12.5%", LocaleUS, 0.125},
+		{"-$5", LocaleUS, -5},
+		{"USD 1,000", LocaleUS, 1000},
+		{"1.234,56 €", LocaleEU, 1234.56},
+		{"1 234,56", LocaleEU, 1234.56},
+		{"300-", LocaleUS, -300},
+	}
+	for _, c := range cases {
+		got, err := ParseFormattedNumber(c.s, c.loc)
+		if err != nil || got != c.want {
+			t.Error(c.s, "should parse as", c.want, "is", got, err)
+		}
+	}
+	for _, s := range []string{"", "abc", "$", "1.2.3x"} {
+		if _, err := ParseFormattedNumber(s, LocaleUS); err != ErrFormattedNumber {
+			t.Error(s, "should not parse, got", err)
+		}
+	}
+}
+
+func TestParseBoolAndDurationText(t *testing.T) {
+	for s, want := range map[string]bool{"TRUE": true, "no": false, "Y": true, "0": false} {
+		if got, err := ParseBoolText(s); err != nil || got != want {
+			t.Error(s, "should be", want, "is", got, err)
+		}
+	}
+	if _, err := ParseBoolText("maybe"); err != ErrBoolText {
+		t.Error("expected ErrBoolText, got", err)
+	}
+	if d, err := ParseDurationText("-01:02:03.5"); err != nil || d != -(time.Hour+2*time.Minute+3500*time.Millisecond) {
+		t.Error("unexpected duration", d, err)
+	}
+	if _, err := ParseDurationText("1.5:00"); err != ErrDurationText {
+		t.Error("expected ErrDurationText, got", err)
+	}
+}
+
+func TestParsedSheet_Cell(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"NUMBER", "TEXT", "BOOL", "FORMULA", "ERROR"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{1.5, "abc", true, 3, 0})
+	_ = f.SetCellFormula(sheet, "D2", "A2*2")
+	_ = f.SetCellFormula(sheet, "E2", "1/0")
+	// Excel stores the cached error value of the formula.
+	_, _ = f.GetCellStyle(sheet, "A1")
+	path, _ := worksheetPath(f, sheet)
+	for i, c := range f.Sheet[path].SheetData.Row[1].C {
+		if c.R == "E2" {
+			f.Sheet[path].SheetData.Row[1].C[i].T = "e"
+			f.Sheet[path].SheetData.Row[1].C[i].V = "#DIV/0!"
+		}
+	}
+	ps, err := MakeParsedSheet(f, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTypes := []CellType{CellTypeNumber, CellTypeString, CellTypeBool, CellTypeNumber, CellTypeError}
+	for c, want := range wantTypes {
+		cell, err := ps.Cell(1, c)
+		if err != nil || cell.Type != want {
+			t.Error("cell", c, "should be", want, "is", cell.Type, err)
+		}
+	}
+	if cell, _ := ps.Cell(1, 3); !cell.IsFormula() || cell.Formula != "A2*2" {
+		t.Error("expected formula A2*2, got", cell.Formula)
+	}
+	if cell, _ := ps.Cell(1, 4); cell.Error != "#DIV/0!" {
+		t.Error("expected #DIV/0!, got", cell.Error)
+	}
+	if _, err := ps.ParsedFloat(1, 4); !errors.Is(err, ErrCellError) {
+		t.Error("expected ErrCellError, got", err)
+	}
+	if f, err := ps.ParsedFloat(1, 0); err != nil || f != 1.5 {
+		t.Error("expected 1.5, got", f, err)
+	}
+	ps.ApplyPrefixColumn("SOURCE", func() string { return "src" })
+	if cell, _ := ps.Cell(1, 0); cell.Type != CellTypeString || cell.Raw != "src" {
+		t.Error("prefix column cell should be a string, is", cell)
+	}
+	if cell, _ := ps.Cell(1, 5); cell.Type != CellTypeError {
+		t.Error("cells should shift with the prefix column, is", cell)
+	}
+}
+
+func TestMakeParsedSheetWithOptions(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"VALUE", "TRIPLE", "RATIO"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{2})
+	_ = f.SetCellFormula(sheet, "B2", "A2*3")
+	_ = f.SetCellFormula(sheet, "C2", "A2/0")
+
+	ps, err := MakeParsedSheetWithOptions(f, sheet, ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.Original[1][1] != "" {
+		t.Error("formula without a cached value should be empty, is", ps.Original[1][1])
+	}
+	if cols := ps.FormulaColumns(); len(cols) != 2 || cols[0] != 1 || cols[1] != 2 {
+		t.Error("expected formula columns [1 2], got", cols)
+	}
+
+	ps, err = MakeParsedSheetWithOptions(f, sheet, ParseOptions{Formulas: true, Evaluate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := ps.ParsedFloat(1, 1); err != nil || v != 6 {
+		t.Error("evaluated formula should be 6, is", v, err)
+	}
+	if cell, _ := ps.Cell(1, 1); cell.Formula != "A2*3" || cell.Type != CellTypeNumber {
+		t.Error("unexpected formula cell", cell)
+	}
+	if _, err := ps.ParsedFloat(1, 2); !errors.Is(err, ErrCellError) {
+		t.Error("expected ErrCellError, got", err)
+	}
+}
+
+func TestMakeParsedSheetEvaluatesBeforeShaping(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"VALUE"})
+	_ = f.SetCellFormula(sheet, "B1", "2020+1")
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{2})
+	_ = f.SetCellFormula(sheet, "B2", "A2*2")
+	_ = f.SetCellFormula(sheet, "A3", "A2+1")
+	_ = f.SetCellFormula(sheet, "B3", "A2*3")
+
+	ps, err := MakeParsedSheetWithOptions(f, sheet, ParseOptions{Evaluate: true, StopAtBlankRow: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps.Original) != 3 {
+		t.Fatal("formula only row should be kept, rows are", ps.Original)
+	}
+	if c, err := ps.ColumnIndex("2021"); err != nil || c != 1 {
+		t.Error("formula header should be 2021, got", ps.Original[0], err)
+	}
+	if v, err := ps.GetFloat(2, "2021"); err != nil || v != 6 {
+		t.Error("expected 6, got", v, err)
+	}
+	if cell, _ := ps.Cell(2, 0); cell.Type != CellTypeNumber || cell.Raw != "3" {
+		t.Error("unexpected evaluated cell", cell)
+	}
+}
+
+func TestMakeParsedSheetWithAnnotations(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"TICKET", "STATUS", "PRIORITY"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"T-1", "Open", "High"})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{"T-2", "Closed", "Low"})
+	_ = f.SetSheetRow(sheet, "E1", &[]interface{}{"High"})
+	_ = f.SetSheetRow(sheet, "E2", &[]interface{}{"Low"})
+	_ = f.SetCellHyperLink(sheet, "A2", "https://example.com/T-1", "External")
+	_ = f.SetCellHyperLink(sheet, "A3", sheet+"!B3", "Location")
+	_ = f.AddComment(sheet, "B2", `{"author":"Reviewer","text":"Check status"}`)
+	statuses := excelize.NewDataValidation(true)
+	statuses.Sqref = "B2:B1048576"
+	_ = statuses.SetDropList([]string{"Open", "Closed"})
+	_ = f.AddDataValidation(sheet, statuses)
+	priorities := excelize.NewDataValidation(true)
+	priorities.Sqref = "C2:C3"
+	_ = priorities.SetSqrefDropList("$E$1:$E$2", true)
+	_ = f.AddDataValidation(sheet, priorities)
+	path := filepath.Join(t.TempDir(), "annotations.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps, err := MakeParsedSheetWithOptions(f, sheet, ParseOptions{Comments: true, Hyperlinks: true, Validations: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target, ok := ps.Hyperlink(1, 0); !ok || target != "https://example.com/T-1" {
+		t.Error("unexpected external hyperlink", target)
+	}
+	if target, ok := ps.Hyperlink(2, 0); !ok || target != sheet+"!B3" {
+		t.Error("unexpected location hyperlink", target)
+	}
+	if comment, ok := ps.Comment(1, 1); !ok || comment.Author != "Reviewer" || comment.Text != "Check status" {
+		t.Error("unexpected comment", comment)
+	}
+	if list, ok := ps.Validation(2, 1); !ok || fmt.Sprint(list) != "[Open Closed]" {
+		t.Error("unexpected drop down list", list)
+	}
+	if len(ps.Validations) != 4 {
+		t.Error("validations should be clipped to the parsed data, got", len(ps.Validations))
+	}
+	if list, ok := ps.Validation(1, 2); !ok || fmt.Sprint(list) != "[High Low]" {
+		t.Error("unexpected range drop down list", list)
+	}
+	if _, ok := ps.Hyperlink(1, 1); ok {
+		t.Error("cell without a hyperlink should not have a target")
+	}
+}
+
+func TestValidationListEscapes(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"TEAM"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"R&D"})
+	teams := excelize.NewDataValidation(true)
+	teams.Sqref = "A2"
+	teams.Type = "list"
+	teams.Formula1 = `<formula1>"R&amp;D,Say &quot;&quot;Hi&quot;&quot;,A&lt;B"</formula1>`
+	_ = f.AddDataValidation(sheet, teams)
+	path := filepath.Join(t.TempDir(), "escapes.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps, err := MakeParsedSheetWithOptions(f, sheet, ParseOptions{Validations: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, ok := ps.Validation(1, 0)
+	if !ok || len(list) != 3 || list[0] != "R&D" || list[1] != `Say "Hi"` || list[2] != "A<B" {
+		t.Errorf("unexpected drop down list %q", list)
+	}
+}
+
+func TestAnnotationsOfReparsedSheet(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"RATE", "CHOICE"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{0.5, 0.25})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{0.25, 0.5})
+	percent, _ := f.NewStyle(&excelize.Style{NumFmt: 10})
+	_ = f.SetCellStyle(sheet, "A2", "B3", percent)
+	_ = f.AddComment(sheet, "B2", `{"author":"Reviewer","text":"Check rate"}`)
+	choices := excelize.NewDataValidation(true)
+	choices.Sqref = "B2:B3"
+	_ = choices.SetSqrefDropList("$A$2:$A$3", true)
+	_ = f.AddDataValidation(sheet, choices)
+
+	for i := 0; i < 2; i++ {
+		ps, err := MakeParsedSheetWithOptions(f, sheet, ParseOptions{Comments: true, Validations: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(ps.Original) != "[[RATE CHOICE] [50.00% 25.00%] [25.00% 50.00%]]" {
+			t.Error("parse", i, "unexpected original format", ps.Original)
+		}
+		if fmt.Sprint(ps.DecimalFormat) != "[[RATE CHOICE] [0.5 0.25] [0.25 0.5]]" {
+			t.Error("parse", i, "unexpected decimal format", ps.DecimalFormat)
+		}
+		if list, ok := ps.Validation(1, 1); !ok || fmt.Sprint(list) != "[50.00% 25.00%]" {
+			t.Error("parse", i, "unexpected drop down list", list)
+		}
+		if comment, ok := ps.Comment(1, 1); !ok || comment.Text != "Check rate" {
+			t.Error("parse", i, "unexpected comment", comment)
+		}
+	}
+	if style, _ := f.GetCellStyle(sheet, "A2"); style != percent {
+		t.Error("parsing should keep the cell styles, A2 has", style)
+	}
+}
+
+func TestCellIsDate(t *testing.T) {
+	for code, want := range map[string]bool{
+		"General":              false,
+		"$#,##0.00":            false,
+		"0.00%":                false,
+		"m/d/yy h:mm":          true,
+		"yyyy-mm-dd":           true,
+		"h:mm:ss":              true,
+		"[h]:mm":               false,
+		`0.0 "days"`:           false,
+		"[$-409]mmm d, yyyy":   true,
+		`#,##0_);[Red](#,##0)`: false,
+	} {
+		if got := (Cell{Type: CellTypeNumber, NumFmtCode: code}).IsDate(); got != want {
+			t.Error(code, "should be a date", want, "is", got)
+		}
+	}
+	if (Cell{Type: CellTypeString, NumFmtCode: "yyyy-mm-dd"}).IsDate() {
+		t.Error("text cells should not be dates")
+	}
+}
+
+func TestCellStyle(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"TICKET"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"T-1"})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{"T-2"})
+	_ = f.SetSheetRow(sheet, "A4", &[]interface{}{"T-3"})
+	highlight, _ := f.NewStyle(`{"fill":{"type":"pattern","color":["#FFFF00"],"pattern":1},"font":{"bold":true,"color":"#FF0000"}}`)
+	struck, _ := f.NewStyle(`{"font":{"strike":true,"italic":true}}`)
+	_ = f.SetCellStyle(sheet, "A2", "A2", highlight)
+	_ = f.SetCellStyle(sheet, "A3", "A3", struck)
+	themed, _ := f.NewStyle(`{"fill":{"type":"pattern","color":["#000000"],"pattern":1}}`)
+	// Fills colored from the theme palette have no RGB color.
+	themeColor := 4
+	themedFill := f.Styles.Fills.Fill[*f.Styles.CellXfs.Xf[themed].FillID].PatternFill
+	themedFill.FgColor.RGB, themedFill.FgColor.Theme = "", &themeColor
+	_ = f.SetCellStyle(sheet, "A4", "A4", themed)
+	path := filepath.Join(t.TempDir(), "styles.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := MakeParsedSheetFromPath(path, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CellStyle{FillColor: "FFFF00", Filled: true, FontColor: "FF0000", Bold: true}
+	if cell, _ := ps.Cell(1, 0); cell.Style != want {
+		t.Error("style should be", want, "is", cell.Style)
+	}
+	// excelize writes black as the font color when none is given.
+	want = CellStyle{FontColor: "000000", Italic: true, Strike: true}
+	if cell, _ := ps.Cell(2, 0); cell.Style != want {
+		t.Error("style should be", want, "is", cell.Style)
+	}
+	if cell, _ := ps.Cell(0, 0); cell.Style.IsFilled() || cell.Style.Bold {
+		t.Error("header should not be styled", cell.Style)
+	}
+	if cell, _ := ps.Cell(3, 0); !cell.Style.IsFilled() || cell.Style.FillColor != "" {
+		t.Error("theme colored fill should be filled without a color", cell.Style)
+	}
+}
+
+func TestHiddenRowsColumnsAndSheets(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"ID", "HELPER", "VALUE"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{1, "x", 10})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{2, "y", 20})
+	_ = f.SetSheetRow(sheet, "A4", &[]interface{}{3, "z", 30})
+	_ = f.SetRowVisible(sheet, 3, false)
+	_ = f.SetColVisible(sheet, "B", false)
+	f.NewSheet("Lookup")
+	_ = f.SetCellValue("Lookup", "A1", "KEY")
+	_ = f.SetSheetVisible("Lookup", false)
+	path := filepath.Join(t.TempDir(), "hidden.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+
+	pf, err := MakeParsedFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := pf.ParsedSheets[sheet]
+	if len(ps.Original) != 4 || len(ps.Original[0]) != 3 {
+		t.Error("hidden rows and columns should be kept by default")
+	}
+	if fmt.Sprint(ps.HiddenRows) != "[3]" || fmt.Sprint(ps.HiddenColumns) != "[2]" {
+		t.Error("unexpected hidden rows and columns", ps.HiddenRows, ps.HiddenColumns)
+	}
+	if len(pf.HiddenSheets) != 1 || pf.ParsedSheets["Lookup"].Visibility != SheetHidden {
+		t.Error("Lookup should be reported as hidden", pf.HiddenSheets)
+	}
+
+	pf, err = MakeParsedFileWithOptions(path, ParseOptions{SkipHiddenRows: true, SkipHiddenColumns: true, SkipHiddenSheets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pf.ParsedSheets["Lookup"]; ok {
+		t.Error("hidden sheet should be skipped")
+	}
+	ps = pf.ParsedSheets[sheet]
+	if fmt.Sprint(ps.Original) != "[[ID VALUE] [1 10] [3 30]]" {
+		t.Error("unexpected visible data", ps.Original)
+	}
+	if len(ps.Cells) != 3 || ps.Cells[2][1].Raw != "30" {
+		t.Error("cells should match the visible data", ps.Cells)
+	}
+}
+
+func TestMakeParsedRegions(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetCellValue(sheet, "A1", "Management Pack")
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{"REGION", "SALES"})
+	_ = f.SetSheetRow(sheet, "A4", &[]interface{}{"North", 10})
+	_ = f.SetSheetRow(sheet, "A5", &[]interface{}{"South", 20})
+	_ = f.SetSheetRow(sheet, "D3", &[]interface{}{"REGION", "", "COST"})
+	_ = f.SetSheetRow(sheet, "D4", &[]interface{}{"North", "", 5})
+	_ = f.SetSheetRow(sheet, "A8", &[]interface{}{"REGION", "SALES"})
+	_ = f.SetSheetRow(sheet, "A9", &[]interface{}{"East", 30})
+
+	regions, err := MakeParsedRegions(f, sheet, ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		rowOffset, colOffset int
+		original             string
+	}{
+		{0, 0, "[[Management Pack]]"},
+		{2, 0, "[[REGION SALES] [North 10] [South 20]]"},
+		{2, 3, "[[REGION] [North]]"},
+		{2, 5, "[[COST] [5]]"},
+		{7, 0, "[[REGION SALES] [East 30]]"},
+	}
+	if len(regions) != len(want) {
+		t.Fatal("expected", len(want), "regions, got", len(regions))
+	}
+	for i, w := range want {
+		region := regions[i]
+		if region.RowOffset != w.rowOffset || region.ColOffset != w.colOffset || fmt.Sprint(region.Original) != w.original {
+			t.Error("unexpected region", i, region.RowOffset, region.ColOffset, region.Original)
+		}
+	}
+	if v, err := regions[4].ParsedFloat(1, 1); err != nil || v != 30 {
+		t.Error("region values should be parsed, got", v, err)
+	}
+
+	ap, err := AggregateAllSheetsDefaultInfo(*regions[1], *regions[4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ap.Items) != 3 || ap.Items[2].OriginalFormat[0] != "East" {
+		t.Error("regions should aggregate", ap.Items)
+	}
+}
+
+func TestFooterOptions(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"REGION", "SALES"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"North", 10})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{"South", 20})
+	_ = f.SetSheetRow(sheet, "A4", &[]interface{}{"Total", 30})
+	_ = f.SetSheetRow(sheet, "A6", &[]interface{}{"", "Grand Total", 30})
+	_ = f.SetSheetRow(sheet, "A8", &[]interface{}{"Notes: figures are unaudited"})
+
+	cases := []struct {
+		opts ParseOptions
+		rows int
+	}{
+		{ParseOptions{}, 8},
+		{ParseOptions{StopAtBlankRow: true}, 4},
+		{ParseOptions{StopAtRow: func(row []string) bool { return TotalRowPattern.MatchString(row[0]) }}, 3},
+		{ParseOptions{TrailingRowPattern: regexp.MustCompile(`(?i)^(notes:|(grand )?total)`)}, 3},
+	}
+	for i, c := range cases {
+		ps, err := MakeParsedSheetWithOptions(f, sheet, c.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ps.Original) != c.rows || len(ps.DecimalFormat) != c.rows || len(ps.Cells) != c.rows {
+			t.Error("case", i, "should have", c.rows, "rows, has", len(ps.Original))
+		}
+	}
+}
+
+func TestAutoNameColumns(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"ID", "", "VALUE"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{1, "note", 10, "", "extra"})
+
+	ps, err := MakeParsedSheet(f, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps.Original[0]) != 1 {
+		t.Error("default parse should stop at the first blank header, has", ps.Original[0])
+	}
+	ps, err = MakeParsedSheetWithOptions(f, sheet, ParseOptions{AutoNameColumns: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ps.Original[0]) != "[ID Column_B VALUE Column_D Column_E]" {
+		t.Error("unexpected headers", ps.Original[0])
+	}
+	if fmt.Sprint(ps.GeneratedHeaders) != "[Column_B Column_D Column_E]" {
+		t.Error("unexpected generated headers", ps.GeneratedHeaders)
+	}
+	if ps.Original[1][4] != "extra" {
+		t.Error("data right of a blank header should be kept", ps.Original[1])
+	}
+
+	_ = f.SetCellValue(sheet, "C1", "Column_B")
+	ps, err = MakeParsedSheetWithOptions(f, sheet, ParseOptions{AutoNameColumns: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ps.Original[0]) != "[ID Column_B_2 Column_B Column_D Column_E]" {
+		t.Error("generated header should not duplicate an existing header", ps.Original[0])
+	}
+	if _, err := ps.ColumnIndex("Column_B"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCellOrigins(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"ID", "DROP", "VALUE"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{1, "x", 10})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{2, "y", 20})
+	_ = f.SetSheetRow(sheet, "A4", &[]interface{}{3, "z", 30})
+	_ = f.SetRowVisible(sheet, 3, false)
+
+	ps, err := MakeParsedSheetWithOptions(f, sheet, ParseOptions{SkipHiddenRows: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps.RemoveColumnFromRowPred(0, func(s string) bool { return s == "DROP" })
+	ps.ApplyPrefixColumn("SOURCE", func() string { return "file" })
+	if addr, err := ps.CellAddress(2, 2); err != nil || addr != "C4" {
+		t.Error("expected C4 for the value of the last row, got", addr, err)
+	}
+	if _, err := ps.CellAddress(1, 0); !errors.Is(err, ErrNoCellOrigin) {
+		t.Error("prefix column should have no origin, got", err)
+	}
+	if _, err := ps.CellAddress(5, 0); !errors.Is(err, ErrInvalidIndices) {
+		t.Error("expected invalid indices, got", err)
+	}
+
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{2, "y", 20})
+	ps, err = MakeParsedSheet(f, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agg, err := AggregateAllSheets(AggregateInfo{Sheet: *ps, StartRow: 1, StartCol: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(agg.Items) != 2 || agg.Items[0].RowIdx != 2 || agg.Items[0].OriginalFormat[1] != "20" {
+		t.Fatal("unexpected aggregation", agg)
+	}
+	if addr, err := agg.Items[1].CellAddress(1); err != nil || addr != "C4" {
+		t.Error("expected C4 for the aggregated value, got", addr, err)
+	}
+}
+
+func TestColumnsByHeader(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"ID", "NAME", "VALUE"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{1, "a", 1.5})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{2, "b", "n/a"})
+
+	ps, err := MakeParsedSheet(f, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col, err := ps.Column("NAME")
+	if err != nil || fmt.Sprint(col.Values()) != "[a b]" {
+		t.Error("unexpected column", col.Values(), err)
+	}
+	if v, err := ps.GetFloat(1, "VALUE"); err != nil || v != 1.5 {
+		t.Error("expected 1.5, got", v, err)
+	}
+	if id, err := ps.GetInt(2, "ID"); err != nil || id != 2 {
+		t.Error("expected 2, got", id, err)
+	}
+	if _, err := ps.GetString(1, "MISSING"); !errors.Is(err, ErrUnknownHeader) {
+		t.Error("expected unknown header, got", err)
+	}
+	valueCol, _ := ps.Column("VALUE")
+	if _, err := valueCol.Floats(); err == nil || err.Error()[:3] != "C3:" {
+		t.Error("expected the error to hold the cell address, got", err)
+	}
+
+	if err := ps.ReorderColumns("VALUE"); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ps.Original[0], ps.DecimalFormat[1]) != "[VALUE ID NAME] [1.5 1 a]" {
+		t.Error("unexpected reorder", ps.Original[0], ps.DecimalFormat[1])
+	}
+	if err := ps.RenameColumns(map[string]string{"NAME": "ID"}); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected duplicate header, got", err)
+	}
+	if err := ps.RenameColumns(map[string]string{"NAME": "ID", "ID": "NAME"}); err != nil {
+		t.Fatal("swapping headers should be valid, got", err)
+	}
+	if fmt.Sprint(ps.Original[0]) != "[VALUE NAME ID]" {
+		t.Error("unexpected swap", ps.Original[0])
+	}
+	if err := ps.RenameColumns(map[string]string{"NAME": "ID", "ID": "LABEL"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.SelectColumns("LABEL", "ID", "LABEL"); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected duplicate header, got", err)
+	}
+	if err := ps.SelectColumns("LABEL", "ID"); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ps.Original) != "[[LABEL ID] [a 1] [b 2]]" {
+		t.Error("unexpected selection", ps.Original)
+	}
+	if addr, _ := ps.CellAddress(2, 0); addr != "B3" {
+		t.Error("expected B3, got", addr)
+	}
+	if cell, _ := ps.Cell(0, 0); cell.Raw != "LABEL" {
+		t.Error("cells should be renamed, got", cell.Raw)
+	}
+}
+
+func TestFilterSortDedupe(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"ID", "VALUE"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"a", 10})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{"b", 9})
+	_ = f.SetSheetRow(sheet, "A4", &[]interface{}{"c", ""})
+	_ = f.SetSheetRow(sheet, "A5", &[]interface{}{"d", 100})
+	_ = f.SetSheetRow(sheet, "A6", &[]interface{}{"a", 10})
+
+	ps, err := MakeParsedSheet(f, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Dedupe("ID"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.SortBy("VALUE", false); err != nil {
+		t.Fatal(err)
+	}
+	if col, _ := ps.Column("ID"); fmt.Sprint(col.Values()) != "[d a b c]" {
+		t.Error("expected numeric descending order with blanks last, got", col.Values())
+	}
+	if addr, _ := ps.CellAddress(1, 0); addr != "A5" {
+		t.Error("expected the first row to come from A5, got", addr)
+	}
+	ps.FilterRows(func(row Row) bool {
+		v, err := row.Float("VALUE")
+		return err == nil && v < 50
+	})
+	if fmt.Sprint(ps.Original) != "[[ID VALUE] [a 10] [b 9]]" {
+		t.Error("unexpected filter", ps.Original)
+	}
+	if row, err := ps.Row(2); err != nil || fmt.Sprint(row.Original) != "[b 9]" {
+		t.Error("unexpected row", row, err)
+	}
+	for _, r := range []int{-1, 3} {
+		if _, err := ps.Row(r); !errors.Is(err, ErrInvalidIndices) {
+			t.Error("expected ErrInvalidIndices for row", r, "got", err)
+		}
+	}
+	empty := &ParsedSheet{}
+	if err := empty.Dedupe(); !errors.Is(err, ErrInvalidData) {
+		t.Error("expected ErrInvalidData, got", err)
+	}
+	empty.FilterRows(func(Row) bool { return true })
+
+	ap, err := AggregateAllSheetsDefaultInfo(*ps, *ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ap.Dedupe(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ap.SortBy("VALUE", true); err != nil {
+		t.Fatal(err)
+	}
+	if len(ap.Items) != 2 || ap.Items[0].OriginalFormat[0] != "b" {
+		t.Error("unexpected aggregation", ap.Items)
+	}
+	if _, err := ap.Row(2); !errors.Is(err, ErrInvalidIndices) {
+		t.Error("expected ErrInvalidIndices, got", err)
+	}
+}
+
+func TestAddColumn(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"FIRST", "LAST", "GRAMS"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"Ada", "Lovelace", 1500})
+
+	ps, err := MakeParsedSheet(f, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ps.AddColumn("NAME", 0, func(row Row) (string, string) {
+		first, _ := row.String("FIRST")
+		last, _ := row.String("LAST")
+		return first + " " + last, first + " " + last
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ps.AddColumn("KG", len(ps.Original[0]), func(row Row) (string, string) {
+		g, _ := row.Float("GRAMS")
+		kg := strconv.FormatFloat(g/1000, 'f', -1, 64)
+		return kg, kg
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ps.Original) != "[[NAME FIRST LAST GRAMS KG] [Ada Lovelace Ada Lovelace 1500 1.5]]" {
+		t.Error("unexpected columns", ps.Original)
+	}
+	if kg, err := ps.GetFloat(1, "KG"); err != nil || kg != 1.5 {
+		t.Error("expected 1.5, got", kg, err)
+	}
+	if cell, _ := ps.Cell(1, 4); cell.Type != CellTypeNumber {
+		t.Error("expected a number cell, got", cell.Type)
+	}
+	if addr, _ := ps.CellAddress(1, 3); addr != "C2" {
+		t.Error("expected C2, got", addr)
+	}
+	if err := ps.AddColumn("BAD", 9, nil); !errors.Is(err, ErrInvalidIndices) {
+		t.Error("expected invalid indices, got", err)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	sales := &ParsedSheet{
+		Original:      [][]string{{"CUSTOMER", "AMOUNT", "NOTE"}, {"1", "$10", "a"}, {"2", "$20", "b"}, {"1", "$5", "c"}},
+		DecimalFormat: [][]string{{"CUSTOMER", "AMOUNT", "NOTE"}, {"1", "10", "a"}, {"2", "20", "b"}, {"1", "5", "c"}},
+	}
+	customers := &ParsedSheet{
+		Original:      [][]string{{"NOTE", "CUSTOMER", "NAME"}, {"x", "1", "Acme"}, {"y", "3", "Initech"}},
+		DecimalFormat: [][]string{{"NOTE", "CUSTOMER", "NAME"}, {"x", "1", "Acme"}, {"y", "3", "Initech"}},
+	}
+	inner, err := Join(sales, customers, JoinOptions{Keys: []string{"CUSTOMER"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(inner.Original) != "[[CUSTOMER AMOUNT NOTE_x NOTE_y NAME] [1 $10 a x Acme] [1 $5 c x Acme]]" {
+		t.Error("unexpected inner join", inner.Original)
+	}
+	left, _ := Join(sales, customers, JoinOptions{Type: LeftJoin, Keys: []string{"CUSTOMER"}})
+	if fmt.Sprint(left.DecimalFormat[2]) != "[2 20 b  ]" {
+		t.Error("unexpected left join row", left.DecimalFormat[2])
+	}
+	full, _ := Join(sales, customers, JoinOptions{Type: FullOuterJoin, Keys: []string{"CUSTOMER"}, LeftSuffix: "_sale"})
+	if len(full.Original) != 5 || fmt.Sprint(full.Original[4]) != "[3   y Initech]" || full.Original[0][2] != "NOTE_sale" {
+		t.Error("unexpected full join", full.Original)
+	}
+	if _, err := full.CellAddress(1, 0); !errors.Is(err, ErrNoCellOrigin) {
+		t.Error("joined cells should have no origin, got", err)
+	}
+	if _, err := Join(sales, customers, JoinOptions{Keys: []string{"ID"}}); !errors.Is(err, ErrUnknownHeader) {
+		t.Error("expected unknown header, got", err)
+	}
+
+	ap, _ := AggregateAllSheetsDefaultInfo(*sales)
+	joined, err := Join(ap.ToParsedSheet(), customers, JoinOptions{Keys: []string{"CUSTOMER"}})
+	if err != nil || len(joined.Original) != 3 {
+		t.Error("unexpected aggregation join", joined, err)
+	}
+
+	named := &ParsedSheet{
+		Original:      [][]string{{"ID", "Name", "Name_x"}, {"1", "a", "b"}},
+		DecimalFormat: [][]string{{"ID", "Name", "Name_x"}, {"1", "a", "b"}},
+	}
+	names := &ParsedSheet{
+		Original:      [][]string{{"ID", "Name"}, {"1", "c"}},
+		DecimalFormat: [][]string{{"ID", "Name"}, {"1", "c"}},
+	}
+	if _, err := Join(named, names, JoinOptions{Keys: []string{"ID"}}); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected a suffixed header clashing with an existing header to fail, got", err)
+	}
+	if _, err := Join(names, names, JoinOptions{Keys: []string{"ID"}, LeftSuffix: "_s", RightSuffix: "_s"}); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected equal suffixes to fail, got", err)
+	}
+}
+
+func TestGroupByAndPivot(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"REGION", "MONTH", "AMOUNT"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"East", "Jan", 10})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{"West", "Jan", 5})
+	_ = f.SetSheetRow(sheet, "A4", &[]interface{}{"East", "Feb", 30})
+	_ = f.SetSheetRow(sheet, "A5", &[]interface{}{"East", "Feb", "n/a"})
+	_ = f.SetSheetRow(sheet, "A6", &[]interface{}{"West", "Feb", ""})
+
+	ps, err := MakeParsedSheet(f, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, diag, err := ps.GroupBy("REGION").Agg(
+		Aggregation{Header: "AMOUNT", Func: AggSum},
+		Aggregation{Header: "AMOUNT", Func: AggCount},
+		Aggregation{Header: "AMOUNT", Func: AggMean, Name: "AVERAGE"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(summary.Original) != "[[REGION AMOUNT_sum AMOUNT_count AVERAGE] [East 40 3 20] [West 5 1 5]]" {
+		t.Error("unexpected summary", summary.Original)
+	}
+	if diag.NonNumeric["AMOUNT"] != 1 || fmt.Sprint(diag.NonNumericCells["AMOUNT"]) != "[C5]" || diag.Blank["AMOUNT"] != 1 {
+		t.Error("unexpected diagnostics", diag)
+	}
+	if _, _, err := ps.GroupBy("MISSING").Agg(); !errors.Is(err, ErrUnknownHeader) {
+		t.Error("expected unknown header, got", err)
+	}
+	sum := Aggregation{Header: "AMOUNT", Func: AggSum}
+	if _, _, err := ps.GroupBy("REGION").Agg(sum, sum); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected identical aggregations to clash, got", err)
+	}
+	if _, _, err := ps.GroupBy("REGION").Agg(Aggregation{Header: "AMOUNT", Name: "REGION"}); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected an aggregation named after a key to clash, got", err)
+	}
+
+	ap, _ := AggregateAllSheetsDefaultInfo(*ps)
+	pivot, diag, err := ap.Pivot("REGION", "MONTH", "AMOUNT", AggMax)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(pivot.Original) != "[[REGION Jan Feb] [East 10 30] [West 5 ]]" {
+		t.Error("unexpected pivot", pivot.Original)
+	}
+	if !diag.HasIssues() || diag.NonNumeric["AMOUNT"] != 1 || len(diag.NonNumericCells["AMOUNT"]) != 0 {
+		t.Error("unexpected pivot diagnostics", diag)
+	}
+
+	if _, _, err := ps.Pivot("MONTH", "REGION", "AMOUNT", AggSum); err != nil {
+		t.Error(err)
+	}
+	ps.Original[2][0] = "MONTH"
+	if _, _, err := ps.Pivot("MONTH", "REGION", "AMOUNT", AggSum); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected a column value equal to the row key to clash, got", err)
+	}
+	ps.Original[2][0] = ""
+	if _, _, err := ps.Pivot("MONTH", "REGION", "AMOUNT", AggSum); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected a blank column value to be rejected, got", err)
+	}
+	ps.Original[2][0] = "East"
+	if _, _, err := ps.Pivot("MONTH", "REGION", "AMOUNT", AggSum); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected values with the same display text to clash, got", err)
+	}
+}
+
+func TestMeltAndTranspose(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"ACCOUNT", "Jan", "Feb"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"Rent", 1000, 1000})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{"Travel", 250.5, ""})
+
+	ps, err := MakeParsedSheet(f, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long, err := ps.Melt([]string{"ACCOUNT"}, nil, "MONTH", "BUDGET")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(long.Original) != "[[ACCOUNT MONTH BUDGET] [Rent Jan 1000] [Rent Feb 1000] [Travel Jan 250.5] [Travel Feb ]]" {
+		t.Error("unexpected melt", long.Original)
+	}
+	if v, err := long.GetFloat(3, "BUDGET"); err != nil || v != 250.5 {
+		t.Error("expected 250.5, got", v, err)
+	}
+	if addr, _ := long.CellAddress(3, 0); addr != "A3" {
+		t.Error("expected A3, got", addr)
+	}
+	if _, err := ps.Melt([]string{"ACCOUNT"}, nil, "ACCOUNT", "BUDGET"); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected duplicate header, got", err)
+	}
+
+	wide := ps.Transpose()
+	if fmt.Sprint(wide.Original) != "[[ACCOUNT Rent Travel] [Jan 1000 250.5] [Feb 1000 ]]" {
+		t.Error("unexpected transpose", wide.Original)
+	}
+	if v, err := wide.GetFloat(2, "Rent"); err != nil || v != 1000 {
+		t.Error("expected 1000, got", v, err)
+	}
+
+	prices := &ParsedSheet{
+		Original:      [][]string{{"PRICE", "QTY"}, {"$1.50", "2"}, {"1,000", "3"}},
+		DecimalFormat: [][]string{{"PRICE", "QTY"}, {"1.5", "2"}, {"1000", "3"}},
+	}
+	byPrice := prices.Transpose()
+	if fmt.Sprint(byPrice.DecimalFormat[0]) != "[PRICE $1.50 1,000]" {
+		t.Error("transposed header should use the original format, got", byPrice.DecimalFormat[0])
+	}
+	if v, err := byPrice.GetFloat(1, "1,000"); err != nil || v != 3 {
+		t.Error("expected 3, got", v, err)
+	}
+	byPrice.DecimalFormat[0][0] = "CHANGED"
+	if byPrice.Original[0][0] != "PRICE" {
+		t.Error("transposed header formats should not share a slice")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := &ParsedSheet{
+		Original:      [][]string{{"ID", "PRICE", "NAME", "OLD"}, {"1", "$10.00", "a", "x"}, {"2", "$20.00", "b", "x"}, {"3", "$30.00", "c", "x"}},
+		DecimalFormat: [][]string{{"ID", "PRICE", "NAME", "OLD"}, {"1", "10", "a", "x"}, {"2", "20", "b", "x"}, {"3", "30", "c", "x"}},
+	}
+	new := &ParsedSheet{
+		Original:      [][]string{{"NAME", "ID", "PRICE"}, {"a", "1", "$10.00"}, {"B", "2", "$20.01"}, {"d", "4", "$40.00"}},
+		DecimalFormat: [][]string{{"NAME", "ID", "PRICE"}, {"a", "1", "10.000000001"}, {"B", "2", "20.01"}, {"d", "4", "40"}},
+	}
+	d, err := DiffWithTolerance(old, new, 0.001, "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(d.Added, d.Removed, d.RemovedHeaders, len(d.Changed)) != "[3] [3] [OLD] 1" {
+		t.Fatal("unexpected diff", d.Added, d.Removed, d.RemovedHeaders, d.Changed)
+	}
+	change := d.Changed[0]
+	if fmt.Sprint(change.Key, change.OldRow, change.NewRow) != "[2] 2 2" || len(change.Changes) != 2 {
+		t.Fatal("unexpected change", change)
+	}
+	if cc := change.Changes[0]; cc.Header != "PRICE" || cc.OldValue != "$20.00" || cc.NewValue != "$20.01" || cc.OldAddress != "B3" || cc.NewAddress != "C3" {
+		t.Error("unexpected cell change", cc)
+	}
+	if d, _ = Diff(old, new, "ID"); len(d.Changed) != 2 {
+		t.Error("expected the price of the first row to change without a tolerance, got", d.Changed)
+	}
+	if _, err := Diff(old, new); !errors.Is(err, ErrNoDiffKeys) {
+		t.Error("expected no diff keys, got", err)
+	}
+
+	repeated := &ParsedSheet{
+		Original:      [][]string{{"ID", "V", "V"}, {"1", "a", "b"}},
+		DecimalFormat: [][]string{{"ID", "V", "V"}, {"1", "a", "b"}},
+	}
+	changed := &ParsedSheet{
+		Original:      [][]string{{"ID", "V", "V"}, {"1", "a", "c"}},
+		DecimalFormat: [][]string{{"ID", "V", "V"}, {"1", "a", "c"}},
+	}
+	if _, err := Diff(repeated, changed, "ID"); !errors.Is(err, ErrDuplicateHeader) {
+		t.Error("expected repeated headers to be rejected, got", err)
+	}
+	repeated.RemoveDuplicateColumnsFromRow(0)
+	changed.RemoveDuplicateColumnsFromRow(0)
+	if _, err := Diff(repeated, changed, "ID"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParsedFloatLocaleText(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"TEXT", "DECIMAL", "NUMBER"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"1.234", "1.234,56", 1.5})
+
+	ps, err := MakeParsedSheet(f, sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for c, expected := range []float64{1234, 1234.56} {
+		s := ps.Original[1][c]
+		if v, err := ps.ParsedFloatLocale(1, c, LocaleEU); err != nil || v != expected {
+			t.Errorf("EU text %q should parse as %v, got %v %v", s, expected, v, err)
+		}
+		rat, err := ps.ParsedRatLocale(1, c, LocaleEU)
+		if f, _ := rat.Float64(); err != nil || f != expected {
+			t.Errorf("EU text %q should parse as rat %v, got %v %v", s, expected, rat, err)
+		}
+	}
+	if v, err := ps.ParsedFloatLocale(1, 2, LocaleEU); err != nil || v != 1.5 {
+		t.Error("numeric cells should use their decimal format, got", v, err)
+	}
+
+	untyped := &ParsedSheet{
+		Original:      [][]string{{"TEXT"}, {"1.234"}},
+		DecimalFormat: [][]string{{"TEXT"}, {"1.234"}},
+	}
+	if v, err := untyped.ParsedFloatLocale(1, 0, LocaleEU); err != nil || v != 1234 {
+		t.Error("EU locale should be applied to cells of unknown type, got", v, err)
+	}
+	if v, err := untyped.ParsedFloatLocale(1, 0, LocaleUS); err != nil || v != 1.234 {
+		t.Error("US locale should read the decimal format, got", v, err)
+	}
+}
@@ -0,0 +1,151 @@
+package parse
+
+import (
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+// cellRange is an inclusive zero based range of a grid.
+type cellRange struct {
+	startRow, startCol, endRow, endCol int
+}
+
+// trimRange shrinks the range to the bounds of its non blank cells.
+func trimRange(grid [][]string, cr cellRange) (cellRange, bool) {
+	trimmed := cellRange{startRow: -1, startCol: -1, endRow: -1, endCol: -1}
+	for r := cr.startRow; r <= cr.endRow; r++ {
+		for c := cr.startCol; c <= cr.endCol; c++ {
+			if grid[r][c] == "" {
+				continue
+			}
+			if trimmed.startRow == -1 {
+				trimmed.startRow = r
+			}
+			trimmed.endRow = r
+			if trimmed.startCol == -1 || c < trimmed.startCol {
+				trimmed.startCol = c
+			}
+			if c > trimmed.endCol {
+				trimmed.endCol = c
+			}
+		}
+	}
+	return trimmed, trimmed.startRow != -1
+}
+
+// splitRange splits the range on every blank row, or on every blank column when byCol is true.
+func splitRange(grid [][]string, cr cellRange, byCol bool) []cellRange {
+	start, end := cr.startRow, cr.endRow
+	if byCol {
+		start, end = cr.startCol, cr.endCol
+	}
+	isBlank := func(i int) bool {
+		if byCol {
+			for r := cr.startRow; r <= cr.endRow; r++ {
+				if grid[r][i] != "" {
+					return false
+				}
+			}
+			return true
+		}
+		for c := cr.startCol; c <= cr.endCol; c++ {
+			if grid[i][c] != "" {
+				return false
+			}
+		}
+		return true
+	}
+	ranges := make([]cellRange, 0)
+	partStart := -1
+	for i := start; i <= end+1; i++ {
+		if i <= end && !isBlank(i) {
+			if partStart == -1 {
+				partStart = i
+			}
+			continue
+		}
+		if partStart == -1 {
+			continue
+		}
+		part := cr
+		if byCol {
+			part.startCol, part.endCol = partStart, i-1
+		} else {
+			part.startRow, part.endRow = partStart, i-1
+		}
+		ranges = append(ranges, part)
+		partStart = -1
+	}
+	return ranges
+}
+
+// findRegions returns the ranges of every island of non blank cells separated by blank rows or columns.
+// Islands are split on blank rows first and then on blank columns until they can not be split further.
+func findRegions(grid [][]string, cr cellRange) []cellRange {
+	cr, ok := trimRange(grid, cr)
+	if !ok {
+		return nil
+	}
+	for _, byCol := range []bool{false, true} {
+		parts := splitRange(grid, cr, byCol)
+		if len(parts) < 2 {
+			continue
+		}
+		regions := make([]cellRange, 0)
+		for _, part := range parts {
+			regions = append(regions, findRegions(grid, part)...)
+		}
+		return regions
+	}
+	return []cellRange{cr}
+}
+
+// region returns a copy of the range of the data as its own ParsedSheet.
+func (ps *ParsedSheet) region(cr cellRange) *ParsedSheet {
+	copyStrings := func(data [][]string) [][]string {
+		copied := make([][]string, 0, cr.endRow-cr.startRow+1)
+		for r := cr.startRow; r <= cr.endRow; r++ {
+			copied = append(copied, append([]string(nil), data[r][cr.startCol:cr.endCol+1]...))
+		}
+		return copied
+	}
+	region := *ps
+	region.Original = copyStrings(ps.Original)
+	region.DecimalFormat = copyStrings(ps.DecimalFormat)
+	region.Cells = make([][]Cell, 0, cr.endRow-cr.startRow+1)
+	for r := cr.startRow; r <= cr.endRow; r++ {
+		region.Cells = append(region.Cells, append([]Cell(nil), ps.Cells[r][cr.startCol:cr.endCol+1]...))
+	}
+	region.RowOffset, region.ColOffset = ps.RowOffset+cr.startRow, ps.ColOffset+cr.startCol
+	region.RowOrigins = append([]int(nil), ps.rowOrigins()[cr.startRow:cr.endRow+1]...)
+	region.ColOrigins = append([]int(nil), ps.colOrigins()[cr.startCol:cr.endCol+1]...)
+	region.HiddenRows = keepOrigins(ps.HiddenRows, region.RowOrigins)
+	region.HiddenColumns = keepOrigins(ps.HiddenColumns, region.ColOrigins)
+	return &region
+}
+
+// MakeParsedRegions returns a ParsedSheet for every table of a sheet.
+// Tables are islands of cells separated by blank rows or columns, such as the
+// several tables of a management pack. Each table's first row is its header and
+// RowOffset and ColOffset hold the table's position on the sheet.
+//	Tables are ordered from top to bottom then left to right.
+//	Note: a title above a table is returned as its own single row table.
+//	StopAtBlankRow has no effect as tables already end at blank rows.
+func MakeParsedRegions(f *excelize.File, sheet string, opts ParseOptions) ([]*ParsedSheet, error) {
+	ps, err := parseSheet(f, sheet, opts, shapeUsedWidth)
+	if err != nil {
+		return nil, err
+	}
+	whole := cellRange{endRow: len(ps.Original) - 1, endCol: len(ps.Original[0]) - 1}
+	regions := make([]*ParsedSheet, 0)
+	for _, cr := range findRegions(ps.Original, whole) {
+		region := ps.region(cr)
+		region.applyFooterOptions(opts)
+		// Columns are named before hidden columns are removed to keep their worksheet names.
+		if opts.AutoNameColumns {
+			region.autoNameColumns()
+		}
+		region.skipHidden(opts)
+		regions = append(regions, region)
+	}
+	return regions, nil
+}
@@ -0,0 +1,134 @@
+package parse
+
+import (
+	"fmt"
+)
+
+// Melt returns a new long form ParsedSheet of a wide sheet. Every data row becomes a row for each
+// value header holding the id columns, the value header in a column named varName and the
+// cell's values in a column named valueName.
+// All columns which are not id columns are melted when no value headers are given.
+//	Rows keep the worksheet row of the row they came from. The varName and valueName columns
+//	have no worksheet origin as their cells come from several columns.
+func (ps *ParsedSheet) Melt(idHeaders, valueHeaders []string, varName, valueName string) (*ParsedSheet, error) {
+	if len(ps.Original) == 0 {
+		return nil, ErrInvalidData
+	}
+	idCols, err := ps.columnIndices(idHeaders)
+	if err != nil {
+		return nil, err
+	}
+	if len(valueHeaders) == 0 {
+		isID := make(map[int]bool)
+		for _, c := range idCols {
+			isID[c] = true
+		}
+		for c, h := range ps.Original[0] {
+			if !isID[c] {
+				valueHeaders = append(valueHeaders, h)
+			}
+		}
+	}
+	valueCols, err := ps.columnIndices(valueHeaders)
+	if err != nil {
+		return nil, err
+	}
+	header := append(append([]string(nil), idHeaders...), varName, valueName)
+	seen := make(map[string]bool)
+	for _, h := range header {
+		if seen[h] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateHeader, h)
+		}
+		seen[h] = true
+	}
+
+	melted := &ParsedSheet{
+		Original:      [][]string{header},
+		DecimalFormat: [][]string{append([]string(nil), header...)},
+		Name:          ps.Name,
+		Path:          ps.Path,
+		FileName:      ps.FileName,
+		Date1904:      ps.Date1904,
+		RowOrigins:    []int{ps.RowOrigin(0)},
+	}
+	headerCells := make([]Cell, 0, len(header))
+	for _, h := range header {
+		headerCells = append(headerCells, textCell(h))
+	}
+	if ps.Cells != nil {
+		melted.Cells = [][]Cell{headerCells}
+	}
+	for r := 1; r < len(ps.Original); r++ {
+		for i, vc := range valueCols {
+			original := make([]string, 0, len(header))
+			decimal := make([]string, 0, len(header))
+			var cells []Cell
+			for _, c := range idCols {
+				original = append(original, ps.Original[r][c])
+				decimal = append(decimal, ps.DecimalFormat[r][c])
+				if ps.Cells != nil {
+					cells = append(cells, ps.Cells[r][c])
+				}
+			}
+			original = append(original, valueHeaders[i], ps.Original[r][vc])
+			decimal = append(decimal, valueHeaders[i], ps.DecimalFormat[r][vc])
+			melted.Original = append(melted.Original, original)
+			melted.DecimalFormat = append(melted.DecimalFormat, decimal)
+			if ps.Cells != nil {
+				melted.Cells = append(melted.Cells, append(cells, textCell(valueHeaders[i]), ps.Cells[r][vc]))
+			}
+			melted.RowOrigins = append(melted.RowOrigins, ps.RowOrigin(r))
+		}
+	}
+	melted.ColOrigins = make([]int, 0, len(header))
+	for _, c := range idCols {
+		melted.ColOrigins = append(melted.ColOrigins, ps.ColOrigin(c))
+	}
+	melted.ColOrigins = append(melted.ColOrigins, 0, 0)
+	return melted, nil
+}
+
+// Transpose returns a new ParsedSheet with the rows and columns of the data swapped so
+// the first column becomes the header row and the header row becomes the first column.
+//	The result has no worksheet origins as each of its rows spans several worksheet rows.
+func (ps *ParsedSheet) Transpose() *ParsedSheet {
+	transposeStrings := func(data [][]string) [][]string {
+		if len(data) == 0 {
+			return [][]string{}
+		}
+		transposed := make([][]string, len(data[0]))
+		for c := range transposed {
+			transposed[c] = make([]string, len(data))
+			for r := range data {
+				transposed[c][r] = data[r][c]
+			}
+		}
+		return transposed
+	}
+	transposed := &ParsedSheet{
+		Original:      transposeStrings(ps.Original),
+		DecimalFormat: transposeStrings(ps.DecimalFormat),
+		Name:          ps.Name,
+		Path:          ps.Path,
+		FileName:      ps.FileName,
+		Date1904:      ps.Date1904,
+	}
+	if len(transposed.Original) > 0 {
+		// Headers are the originally formatted values in every format.
+		transposed.DecimalFormat[0] = append([]string(nil), transposed.Original[0]...)
+	}
+	if ps.Cells != nil && len(ps.Cells) > 0 {
+		transposed.Cells = make([][]Cell, len(ps.Cells[0]))
+		for c := range transposed.Cells {
+			transposed.Cells[c] = make([]Cell, len(ps.Cells))
+			for r := range ps.Cells {
+				transposed.Cells[c][r] = ps.Cells[r][c]
+			}
+		}
+	}
+	transposed.RowOrigins = make([]int, len(transposed.Original))
+	if len(transposed.Original) > 0 {
+		transposed.ColOrigins = make([]int, len(transposed.Original[0]))
+	}
+	return transposed
+}
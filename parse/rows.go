@@ -0,0 +1,288 @@
+package parse
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Row is a data row of a ParsedSheet or AggregatedParse with its values found by header.
+type Row struct {
+	Header        []string
+	Original      []string
+	DecimalFormat []string
+}
+
+// index returns the index of the first column with the header or -1.
+func (row Row) index(header string) int {
+	for c, h := range row.Header {
+		if h == header {
+			return c
+		}
+	}
+	return -1
+}
+
+// String returns the originally formatted value of the column with the header.
+func (row Row) String(header string) (string, error) {
+	c := row.index(header)
+	if c < 0 || c >= len(row.Original) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownHeader, header)
+	}
+	return row.Original[c], nil
+}
+
+// Float parses the decimal formatted value of the column with the header as a float64.
+func (row Row) Float(header string) (float64, error) {
+	c := row.index(header)
+	if c < 0 || c >= len(row.DecimalFormat) {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownHeader, header)
+	}
+	return strconv.ParseFloat(row.DecimalFormat[c], 64)
+}
+
+// key returns the decimal formatted values of the columns with the given indices joined as a map key.
+func (row Row) key(indices []int) string {
+	values := make([]string, 0, len(indices))
+	for _, c := range indices {
+		if c < len(row.DecimalFormat) {
+			values = append(values, row.DecimalFormat[c])
+		} else {
+			values = append(values, "")
+		}
+	}
+	return strings.Join(values, "\x00")
+}
+
+// compareValues orders decimal formatted values numerically when both are numbers.
+// Numbers are ordered before text and blank values after both.
+func compareValues(a, b string) int {
+	aBlank, bBlank := strings.TrimSpace(a) == "", strings.TrimSpace(b) == ""
+	if aBlank || bBlank {
+		switch {
+		case aBlank && bBlank:
+			return 0
+		case aBlank:
+			return 1
+		}
+		return -1
+	}
+	af, aErr := strconv.ParseFloat(a, 64)
+	bf, bErr := strconv.ParseFloat(b, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		if af < bf {
+			return -1
+		}
+		if af > bf {
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// lessRows returns the less function of a stable sort on the column with the given index.
+//	Blank values are ordered last in both directions.
+func lessRows(rows []Row, c int, asc bool) func(i, j int) bool {
+	return func(i, j int) bool {
+		a, b := rows[i].DecimalFormat[c], rows[j].DecimalFormat[c]
+		if strings.TrimSpace(a) == "" || strings.TrimSpace(b) == "" || asc {
+			return compareValues(a, b) < 0
+		}
+		return compareValues(b, a) < 0
+	}
+}
+
+// keyIndices returns the index of the column of every key header or of every column when none are given.
+func keyIndices(header []string, keyHeaders []string) ([]int, error) {
+	if len(keyHeaders) == 0 {
+		indices := make([]int, len(header))
+		for c := range indices {
+			indices[c] = c
+		}
+		return indices, nil
+	}
+	row := Row{Header: header}
+	indices := make([]int, 0, len(keyHeaders))
+	for _, header := range keyHeaders {
+		c := row.index(header)
+		if c < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownHeader, header)
+		}
+		indices = append(indices, c)
+	}
+	return indices, nil
+}
+
+// Row returns the data row with the given index, where the header is row zero.
+func (ps *ParsedSheet) Row(r int) (Row, error) {
+	if r < 0 || r >= len(ps.Original) {
+		return Row{}, ErrInvalidIndices
+	}
+	return ps.row(r), nil
+}
+
+// row returns the row with the given index without checking it.
+func (ps *ParsedSheet) row(r int) Row {
+	return Row{Header: ps.Original[0], Original: ps.Original[r], DecimalFormat: ps.DecimalFormat[r]}
+}
+
+// dataRows returns every data row of the sheet.
+func (ps *ParsedSheet) dataRows() []Row {
+	rows := make([]Row, 0, len(ps.Original))
+	for r := 1; r < len(ps.Original); r++ {
+		rows = append(rows, ps.row(r))
+	}
+	return rows
+}
+
+// arrangeRows rebuilds every format of the data from the header and the data rows with the given indices in order.
+func (ps *ParsedSheet) arrangeRows(indices []int) {
+	if len(ps.Original) == 0 {
+		return
+	}
+	indices = append([]int{0}, indices...)
+	original := make([][]string, 0, len(indices))
+	decimal := make([][]string, 0, len(indices))
+	rowOrigins := make([]int, 0, len(indices))
+	var cells [][]Cell
+	if ps.Cells != nil {
+		cells = make([][]Cell, 0, len(indices))
+	}
+	for _, r := range indices {
+		original = append(original, ps.Original[r])
+		decimal = append(decimal, ps.DecimalFormat[r])
+		rowOrigins = append(rowOrigins, ps.RowOrigin(r))
+		if cells != nil {
+			cells = append(cells, ps.Cells[r])
+		}
+	}
+	ps.Original, ps.DecimalFormat, ps.Cells = original, decimal, cells
+	ps.RowOrigins = rowOrigins
+}
+
+// FilterRows keeps the data rows for which pred returns true.
+func (ps *ParsedSheet) FilterRows(pred func(Row) bool) {
+	kept := make([]int, 0, len(ps.Original))
+	for i, row := range ps.dataRows() {
+		if pred(row) {
+			kept = append(kept, i+1)
+		}
+	}
+	ps.arrangeRows(kept)
+}
+
+// SortBy sorts the data rows by the decimal formatted values of the column with the header.
+// The sort is stable and orders numbers numerically before text.
+//	Blank values are ordered last in both directions.
+func (ps *ParsedSheet) SortBy(header string, asc bool) error {
+	c, err := ps.ColumnIndex(header)
+	if err != nil {
+		return err
+	}
+	rows := ps.dataRows()
+	indices := make([]int, len(rows))
+	for i := range indices {
+		indices[i] = i + 1
+	}
+	less := lessRows(rows, c, asc)
+	sort.SliceStable(indices, func(i, j int) bool {
+		return less(indices[i]-1, indices[j]-1)
+	})
+	ps.arrangeRows(indices)
+	return nil
+}
+
+// Dedupe keeps the first data row of every distinct set of decimal formatted values in the
+// columns with the key headers, or in every column when no headers are given.
+func (ps *ParsedSheet) Dedupe(keyHeaders ...string) error {
+	if len(ps.Original) == 0 {
+		return ErrInvalidData
+	}
+	indices, err := keyIndices(ps.Original[0], keyHeaders)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	ps.FilterRows(func(row Row) bool {
+		key := row.key(indices)
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+		return true
+	})
+	return nil
+}
+
+// Row returns the item with the given index as a Row of the aggregation.
+func (ap *AggregatedParse) Row(i int) (Row, error) {
+	if i < 0 || i >= len(ap.Items) {
+		return Row{}, ErrInvalidIndices
+	}
+	return ap.row(i), nil
+}
+
+// row returns the item with the given index as a Row without checking it.
+func (ap *AggregatedParse) row(i int) Row {
+	return Row{Header: ap.Header, Original: ap.Items[i].OriginalFormat, DecimalFormat: ap.Items[i].DecimalFormat}
+}
+
+// FilterRows keeps the items for which pred returns true.
+func (ap *AggregatedParse) FilterRows(pred func(Row) bool) {
+	items := make([]AggItem, 0, len(ap.Items))
+	for i := range ap.Items {
+		if pred(ap.row(i)) {
+			items = append(items, ap.Items[i])
+		}
+	}
+	ap.Items = items
+}
+
+// SortBy sorts the items in the same way as ParsedSheet.SortBy.
+func (ap *AggregatedParse) SortBy(header string, asc bool) error {
+	c := Row{Header: ap.Header}.index(header)
+	if c < 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownHeader, header)
+	}
+	rows := make([]Row, len(ap.Items))
+	order := make([]int, len(ap.Items))
+	for i := range ap.Items {
+		rows[i] = ap.row(i)
+		order[i] = i
+	}
+	less := lessRows(rows, c, asc)
+	sort.SliceStable(order, func(i, j int) bool {
+		return less(order[i], order[j])
+	})
+	items := make([]AggItem, 0, len(ap.Items))
+	for _, i := range order {
+		items = append(items, ap.Items[i])
+	}
+	ap.Items = items
+	return nil
+}
+
+// Dedupe keeps the first item of every distinct key in the same way as ParsedSheet.Dedupe.
+func (ap *AggregatedParse) Dedupe(keyHeaders ...string) error {
+	indices, err := keyIndices(ap.Header, keyHeaders)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	ap.FilterRows(func(row Row) bool {
+		key := row.key(indices)
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+		return true
+	})
+	return nil
+}
@@ -22,12 +22,47 @@ var ErrInvalidIndices = errors.New("sheetParse: invalid pair of indices")
 type ParsedSheet struct {
 	Original      [][]string
 	DecimalFormat [][]string
+	// Cells holds the stored value of every cell with the same shape as Original.
+	// Nil when the sheet was not parsed from a workbook.
+	Cells [][]Cell
+	// Comments holds the comment of each cell keyed by worksheet address e.g. "B2".
+	// Nil unless parsed with ParseOptions.Comments.
+	Comments map[string]CellComment
+	// Hyperlinks holds the hyperlink target of each cell keyed by worksheet address.
+	// Nil unless parsed with ParseOptions.Hyperlinks.
+	Hyperlinks map[string]string
+	// Validations holds the drop down list values of each cell keyed by worksheet address.
+	// Nil unless parsed with ParseOptions.Validations.
+	Validations map[string][]string
 	// name of the sheet parsed
 	Name string
 	// Path of the file containing the sheet. Empty if parsed directly from excelize file.
 	Path string
 	// Name of file containing sheet. Empty if parsed directly from excelize file.
 	FileName string
+	// Date1904 is true when the workbook uses the 1904 date system.
+	Date1904 bool
+	// RowOffset and ColOffset are the zero based worksheet position of the first cell of the data.
+	// Both are zero unless the data is a region of the sheet, see MakeParsedRegions.
+	RowOffset int
+	ColOffset int
+	// RowOrigins and ColOrigins hold the worksheet row and column number of every row and column
+	// of the data. They are kept up to date as rows and columns are removed or added, with zero
+	// for columns which were not read from the worksheet. See CellAddress.
+	// Nil when the sheet was not parsed from a workbook, in which case the offsets are used.
+	RowOrigins []int
+	ColOrigins []int
+	// Visibility is the visibility state of the sheet.
+	Visibility SheetVisibility
+	// HiddenRows holds the worksheet row numbers of the hidden rows within the data,
+	// including rows removed by ParseOptions.SkipHiddenRows.
+	HiddenRows []int
+	// HiddenColumns holds the worksheet column numbers of the hidden columns within the data,
+	// including columns removed by ParseOptions.SkipHiddenColumns.
+	HiddenColumns []int
+	// GeneratedHeaders holds the headers named by ParseOptions.AutoNameColumns
+	// because their cell was blank.
+	GeneratedHeaders []string
 }
 
 func applyPrefixColumn(data [][]string, colName string, mapper func()string)[][]string{
@@ -47,18 +82,32 @@ func applyPrefixColumn(data [][]string, colName string, mapper func()string)[][]
 	}
 	return newData
 }
-
 // ApplyPrefixColumn adds a new column to the left of the data.
-//	The first cell is the colName and every subsequent value is the returned value of mapper
+//	The first cell is the colName and every subsequent value is the returned value of mapper.
+//	mapper is called once per row for each of the two formats, original first.
 func (ps *ParsedSheet)ApplyPrefixColumn(colName string, mapper func()string){
 	newOriginal := applyPrefixColumn(ps.Original, colName, mapper)
 	newDecimal := applyPrefixColumn(ps.DecimalFormat, colName, mapper)
 	ps.Original = newOriginal
 	ps.DecimalFormat = newDecimal
+	ps.ColOrigins = append([]int{0}, ps.colOrigins()...)
+	if ps.Cells != nil {
+		cells := make([][]Cell, 0, len(ps.Cells))
+		for idx, row := range ps.Cells {
+			cellRow := make([]Cell, 0, len(row)+1)
+			if idx == 0 {
+				cellRow = append(cellRow, textCell(colName))
+			} else {
+				cellRow = append(cellRow, textCell(ps.Original[idx][0]))
+			}
+			cells = append(cells, append(cellRow, row...))
+		}
+		ps.Cells = cells
+	}
 }
 
 func (ps *ParsedSheet) indexErr(r, c int) error {
-	if r > len(ps.Original) || c > len(ps.Original[0]) {
+	if r < 0 || c < 0 || r >= len(ps.Original) || c >= len(ps.Original[r]) {
 		return ErrInvalidIndices
 	}
 	return nil
@@ -74,10 +123,14 @@ func (ps *ParsedSheet) ParsedString(r, c int) (string, error) {
 
 // ParseFloat attempts to parse the cell value using the decimal number formatted string
 // as a float64.
+//	Cells containing an Excel error value return an error wrapping ErrCellError.
 func (ps *ParsedSheet) ParsedFloat(r, c int) (float64, error) {
 	if err := ps.indexErr(r, c); err != nil {
 		return 0, err
 	}
+	if err := ps.cellErr(r, c); err != nil {
+		return 0, err
+	}
 	return strconv.ParseFloat(ps.DecimalFormat[r][c], 64)
 }
 
@@ -93,12 +146,13 @@ func (ps *ParsedSheet) ParsedInt(r, c int) (int, error) {
 
 // ParseInt attempts to parse the cell value using the decimal number
 //formatted string as an time.Time.
+//	Text cells are not parsed, use ParsedTimeIn with layouts to read dates stored as text.
 func (ps *ParsedSheet) ParsedTime(r, c int) (time.Time, error) {
 	f, err := ps.ParsedFloat(r, c)
 	if err != nil {
 		return time.Time{}, err
 	}
-	return excelize.ExcelDateToTime(f, false)
+	return excelize.ExcelDateToTime(f, ps.Date1904)
 }
 
 // RemoveColumnFromRowPred removes columns of data where the given predicate function
@@ -115,21 +169,70 @@ func (ps *ParsedSheet)RemoveColumnFromRowPred(rIdx int, pred func(s string)bool)
 	if !filterNeeded{
 		return
 	}
-	original, decimal := make([][]string, 0, len(ps.Original)), make([][]string, 0, len(ps.DecimalFormat))
-	for rowIdx := range ps.Original{
-		originalRow, decimalRow := make([]string, 0, len(ps.Original[rowIdx])), make([]string, 0, len(ps.DecimalFormat[rowIdx]))
-		for colIdx := range ps.Original[rowIdx]{
-			if keepMap[colIdx]{
-				continue
+	ps.removeColumns(keepMap)
+}
+
+// removeColumns removes the columns with the given indices from every format of the data.
+func (ps *ParsedSheet) removeColumns(remove map[int]bool) {
+	filterStrings := func(data [][]string) [][]string {
+		filtered := make([][]string, 0, len(data))
+		for rowIdx := range data {
+			row := make([]string, 0, len(data[rowIdx]))
+			for colIdx := range data[rowIdx] {
+				if !remove[colIdx] {
+					row = append(row, data[rowIdx][colIdx])
+				}
 			}
-			originalRow = append(originalRow, ps.Original[rowIdx][colIdx])
-			decimalRow = append(decimalRow, ps.DecimalFormat[rowIdx][colIdx])
+			filtered = append(filtered, row)
 		}
-		original = append(original, originalRow)
-		decimal = append(decimal, decimalRow)
+		return filtered
+	}
+	colOrigins := make([]int, 0, len(ps.colOrigins()))
+	for colIdx, origin := range ps.colOrigins() {
+		if !remove[colIdx] {
+			colOrigins = append(colOrigins, origin)
+		}
+	}
+	ps.ColOrigins = colOrigins
+	ps.Original = filterStrings(ps.Original)
+	ps.DecimalFormat = filterStrings(ps.DecimalFormat)
+	if ps.Cells != nil {
+		cells := make([][]Cell, 0, len(ps.Cells))
+		for rowIdx := range ps.Cells {
+			cellRow := make([]Cell, 0, len(ps.Cells[rowIdx]))
+			for colIdx := range ps.Cells[rowIdx] {
+				if !remove[colIdx] {
+					cellRow = append(cellRow, ps.Cells[rowIdx][colIdx])
+				}
+			}
+			cells = append(cells, cellRow)
+		}
+		ps.Cells = cells
 	}
-	ps.Original = original
-	ps.DecimalFormat = decimal
+}
+
+// removeRows removes the rows with the given indices from every format of the data.
+func (ps *ParsedSheet) removeRows(remove map[int]bool) {
+	original := make([][]string, 0, len(ps.Original))
+	decimal := make([][]string, 0, len(ps.DecimalFormat))
+	rowOrigins := make([]int, 0, len(ps.Original))
+	var cells [][]Cell
+	if ps.Cells != nil {
+		cells = make([][]Cell, 0, len(ps.Cells))
+	}
+	for rowIdx := range ps.Original {
+		if remove[rowIdx] {
+			continue
+		}
+		original = append(original, ps.Original[rowIdx])
+		decimal = append(decimal, ps.DecimalFormat[rowIdx])
+		rowOrigins = append(rowOrigins, ps.RowOrigin(rowIdx))
+		if cells != nil {
+			cells = append(cells, ps.Cells[rowIdx])
+		}
+	}
+	ps.Original, ps.DecimalFormat, ps.Cells = original, decimal, cells
+	ps.RowOrigins = rowOrigins
 }
 // RemoveDuplicateColumnsFromRow removes all columns which have a duplicate value in the row with the given index.
 func (ps *ParsedSheet)RemoveDuplicateColumnsFromRow(rowIdx int){
@@ -156,31 +259,88 @@ func (ps *ParsedSheet)RemoveRightDuplicateColumnsFromRow(rowIdx int){
 // MakeParsedSheet returns a ParsedSheet to provide quick access to both the originally formatted
 // cell values as well as the decimal formatted cell values.
 func MakeParsedSheet(f *excelize.File, sheet string) (*ParsedSheet, error) {
+	return MakeParsedSheetWithOptions(f, sheet, ParseOptions{})
+}
+
+// MakeParsedSheetWithOptions returns a ParsedSheet in the same way as MakeParsedSheet
+// while reading the optional information set in opts.
+func MakeParsedSheetWithOptions(f *excelize.File, sheet string, opts ParseOptions) (*ParsedSheet, error) {
+	ps, err := parseSheet(f, sheet, opts, opts.shape())
+	if err != nil {
+		return nil, err
+	}
+	ps.applyFooterOptions(opts)
+	// Columns are named before hidden columns are removed to keep their worksheet names.
+	if opts.AutoNameColumns {
+		ps.autoNameColumns()
+	}
+	ps.skipHidden(opts)
+	return ps, nil
+}
+
+// parseSheet parses the cells of a sheet after re-dimensioning them with shape.
+func parseSheet(f *excelize.File, sheet string, opts ParseOptions, shape func([][]string) [][]string) (*ParsedSheet, error) {
 	cells, err := f.GetRows(sheet)
 	if err != nil {
 		return nil, err
 	}
-	if len(cells) == 0 || len(cells[0]) == 0 {
+	var evaluated map[cellPosition]string
+	if opts.Evaluate {
+		if evaluated, err = evaluateFormulas(f, sheet); err != nil {
+			return nil, err
+		}
+		cells = withEvaluated(cells, evaluated)
+	}
+	shapedCells := shape(cells)
+	if len(shapedCells) == 0 || len(shapedCells[0]) == 0 {
 		return nil, ErrInvalidData
 	}
-	shapedCells := shapeCells(cells)
 
 	startAdd, _ := excelize.CoordinatesToCellName(1, 1)
 	endAddr, _ := excelize.CoordinatesToCellName(len(shapedCells[0]), len(shapedCells))
 	numberStyle, _ := f.NewStyle(`{"decimal_places":15}`)
+	// Cells must be read before the decimal style replaces the original number formats.
+	typedCells, err := makeCells(f, sheet, shapedCells)
+	if err != nil {
+		return nil, err
+	}
+	ps := &ParsedSheet{
+		Original:   shapedCells,
+		Cells:      typedCells,
+		Name:       sheet,
+		Date1904:   WorkbookDate1904(f),
+		Visibility: sheetVisibility(f, sheet),
+		RowOrigins: offsetOrigins(0, len(shapedCells)),
+		ColOrigins: offsetOrigins(0, len(shapedCells[0])),
+	}
+	// Drop down lists may refer to cells of the sheet itself so are also read before the decimal style.
+	if err := ps.applyAnnotationOptions(f, opts); err != nil {
+		return nil, err
+	}
+	styleIDs, err := cellStyleIDs(f, sheet)
+	if err != nil {
+		return nil, err
+	}
 	err = f.SetCellStyle(sheet, startAdd, endAddr, numberStyle)
 	if err != nil {
 		return nil, err
 	}
 
 	decCells, _ := f.GetRows(sheet)
-	shapedDecCells := shapeCells(decCells)
-
-	return &ParsedSheet{
-		Original:      shapedCells,
-		DecimalFormat: shapedDecCells,
-		Name: sheet,
-	}, nil
+	if err := restoreCellStyles(f, sheet, styleIDs); err != nil {
+		return nil, err
+	}
+	if opts.Evaluate {
+		decCells = withEvaluated(decCells, evaluated)
+	}
+	ps.DecimalFormat = shape(decCells)
+	if err := ps.applyFormulaOptions(f, opts, evaluated); err != nil {
+		return nil, err
+	}
+	if err := ps.recordHidden(f); err != nil {
+		return nil, err
+	}
+	return ps, nil
 }
 
 // MakeParsedSheetFromPath attempts to parse a sheet from a given file path
@@ -193,7 +353,7 @@ func MakeParsedSheetFromPath(path string, sheet string)(*ParsedSheet, error){
 	if err != nil{
 		return nil, err
 	}
-	sht.Name = filepath.Base(path)
+	sht.FileName = filepath.Base(path)
 	sht.Path = path
 	return sht, nil
 }
@@ -224,6 +384,9 @@ func MakeParsedSheetFromPathAndSheetIndex(path string, sheetIdx int)(*ParsedShee
 type ParsedFile struct {
 	ParsedSheets map[string]*ParsedSheet
 	FailedSheets []string
+	// HiddenSheets holds the names of hidden and very hidden sheets,
+	// including sheets skipped by ParseOptions.SkipHiddenSheets.
+	HiddenSheets []string
 	name string
 	path string
 }
@@ -244,16 +407,23 @@ func (pf *ParsedFile)ListParsedSheets()[]ParsedSheet{
 	return sheets
 }
 
-func makeParsedFileSync(path string)(*ParsedFile, error){
+func makeParsedFileSync(path string, opts ParseOptions)(*ParsedFile, error){
 	f, err := excelize.OpenFile(path)
 	if err != nil{
 		return nil, err
 	}
 	parsedSheetMap := make(map[string]*ParsedSheet)
 	failedSheets := make([]string, 0)
+	hiddenSheets := make([]string, 0)
 
 	for _, nm := range f.GetSheetList(){
-		parsedSheet, err := MakeParsedSheet(f, nm)
+		if sheetVisibility(f, nm) != SheetVisible{
+			hiddenSheets = append(hiddenSheets, nm)
+			if opts.SkipHiddenSheets{
+				continue
+			}
+		}
+		parsedSheet, err := MakeParsedSheetWithOptions(f, nm, opts)
 		switch err {
 		case nil:
 			parsedSheetMap[nm] = parsedSheet
@@ -264,6 +434,7 @@ func makeParsedFileSync(path string)(*ParsedFile, error){
 	pf := &ParsedFile{
 		ParsedSheets: parsedSheetMap,
 		FailedSheets: failedSheets,
+		HiddenSheets: hiddenSheets,
 		name:         filepath.Base(path),
 		path:         path,
 	}
@@ -277,12 +448,17 @@ func makeParsedFileSync(path string)(*ParsedFile, error){
 // MakeParsedFile attempts to parse every sheet of a given file path.
 // TODO parse the sheets concurrently to improve performance
 func MakeParsedFile(path string)(*ParsedFile, error){
-	return makeParsedFileSync(path)
+	return makeParsedFileSync(path, ParseOptions{})
 }
 
-// shapeCells calculates the number of columns
-// for a given tabular data structure and
-// re-dimensions each row to have that number of columns
+// MakeParsedFileWithOptions attempts to parse every sheet of a given file path
+// while reading the optional information set in opts.
+func MakeParsedFileWithOptions(path string, opts ParseOptions)(*ParsedFile, error){
+	return makeParsedFileSync(path, opts)
+}
+
+// shapeCells2 re-dimensions each row in the same way as shapeCells
+// but ends the data at the first blank row.
 func shapeCells2(cells [][]string) [][]string {
 	colCount := getColumnCount(cells)
 	for i := range cells {
@@ -306,6 +482,35 @@ func shapeCells(cells [][]string) [][]string {
 	return removeEmptyTrailingRows(cells)
 }
 
+// shapeUsedWidth re-dimensions each row to the used width of the data,
+// which is the last column with a value in any row, so that no data to
+// the right of a blank header is lost.
+func shapeUsedWidth(cells [][]string) [][]string {
+	colCount := 0
+	for _, row := range cells {
+		for c := len(row) - 1; c >= colCount; c-- {
+			if row[c] != "" {
+				colCount = c + 1
+				break
+			}
+		}
+	}
+	for i := range cells {
+		cells[i] = shapeRow(cells[i], colCount)
+	}
+	return removeEmptyTrailingRows(cells)
+}
+
+// endAtBlankRow ends already shaped data at the first blank row.
+func endAtBlankRow(cells [][]string) [][]string {
+	for i := range cells {
+		if rowEmpty(cells[i], len(cells[i])) {
+			return cells[:i]
+		}
+	}
+	return cells
+}
+
 // shapeRow will re-dimension the array of strings
 // to have colCount values.
 //	If colCount < len(r), values will be removed
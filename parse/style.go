@@ -0,0 +1,112 @@
+package parse
+
+import (
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"strings"
+)
+
+// CellStyle summarises the fill and font of a cell.
+// Colors are RGB hex strings e.g. "FFFF00" and are empty when not set.
+// Theme colors are not resolved and are also empty.
+type CellStyle struct {
+	FillColor string
+	// Filled is true for every patterned fill including theme colored fills without a FillColor.
+	Filled    bool
+	FontColor string
+	Bold      bool
+	Italic    bool
+	Strike    bool
+}
+
+// IsFilled returns whether the cell has a fill.
+func (cs CellStyle) IsFilled() bool {
+	return cs.Filled
+}
+
+// indexedColors is the default palette of indexed colors.
+var indexedColors = []string{
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"800000", "008000", "000080", "808000", "800080", "008080", "C0C0C0", "808080",
+	"9999FF", "993366", "FFFFCC", "CCFFFF", "660066", "FF8080", "0066CC", "CCCCFF",
+	"000080", "FF00FF", "FFFF00", "00FFFF", "800080", "800000", "008080", "0000FF",
+	"00CCFF", "CCFFFF", "CCFFCC", "FFFF99", "99CCFF", "FF99CC", "CC99FF", "FFCC99",
+	"3366FF", "33CCCC", "99CC00", "FFCC00", "FF9900", "FF6600", "666699", "969696",
+	"003366", "339966", "003300", "333300", "993300", "993366", "333399", "333333",
+}
+
+// rgbColor returns the RGB hex of an ARGB or RGB color.
+func rgbColor(argb string) string {
+	argb = strings.ToUpper(argb)
+	if len(argb) == 8 {
+		return argb[2:]
+	}
+	return argb
+}
+
+// cellStyle returns the fill and font summary of a style.
+func cellStyle(f *excelize.File, styleID int) CellStyle {
+	var cs CellStyle
+	if f.Styles == nil || f.Styles.CellXfs == nil || styleID >= len(f.Styles.CellXfs.Xf) {
+		return cs
+	}
+	xf := f.Styles.CellXfs.Xf[styleID]
+	if xf.FillID != nil && f.Styles.Fills != nil && *xf.FillID < len(f.Styles.Fills.Fill) {
+		fill := f.Styles.Fills.Fill[*xf.FillID]
+		if fill != nil && fill.PatternFill != nil && fill.PatternFill.PatternType != "" && fill.PatternFill.PatternType != "none" {
+			cs.Filled = true
+			color := fill.PatternFill.FgColor
+			switch {
+			case color.RGB != "":
+				cs.FillColor = rgbColor(color.RGB)
+			case color.Theme == nil && !color.Auto && color.Indexed < len(indexedColors):
+				cs.FillColor = indexedColors[color.Indexed]
+			}
+		}
+	}
+	if xf.FontID != nil && f.Styles.Fonts != nil && *xf.FontID < len(f.Styles.Fonts.Font) {
+		font := f.Styles.Fonts.Font[*xf.FontID]
+		if font == nil {
+			return cs
+		}
+		// Font properties are set by the presence of their element.
+		cs.Bold, cs.Italic, cs.Strike = font.B != nil, font.I != nil, font.Strike != nil
+		if font.Color != nil && font.Color.RGB != "" {
+			cs.FontColor = rgbColor(font.Color.RGB)
+		}
+	}
+	return cs
+}
+
+// cellStyleIDs returns the style id of every cell of the sheet keyed by worksheet address.
+func cellStyleIDs(f *excelize.File, sheet string) (map[string]int, error) {
+	wsPath, err := loadWorksheet(f, sheet)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int)
+	for _, row := range f.Sheet[wsPath].SheetData.Row {
+		for _, c := range row.C {
+			ids[c.R] = c.S
+		}
+	}
+	return ids, nil
+}
+
+// restoreCellStyles sets the style of every cell of the sheet back to the recorded ids.
+// Cells without a recorded id were added by a later style and are given the default style.
+//	Parsing restores the styles so that the number formats, drop down lists and comments
+//	read by later parses of the same file are not changed by the decimal style.
+func restoreCellStyles(f *excelize.File, sheet string, ids map[string]int) error {
+	wsPath, err := loadWorksheet(f, sheet)
+	if err != nil {
+		return err
+	}
+	rows := f.Sheet[wsPath].SheetData.Row
+	for r := range rows {
+		for c := range rows[r].C {
+			rows[r].C[c].S = ids[rows[r].C[c].R]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AggFunc summarises the values of a column within a group.
+type AggFunc int
+
+const (
+	AggSum AggFunc = iota
+	// AggCount counts the non blank cells, numeric or not.
+	AggCount
+	AggMin
+	AggMax
+	AggMean
+)
+
+func (fn AggFunc) String() string {
+	switch fn {
+	case AggCount:
+		return "count"
+	case AggMin:
+		return "min"
+	case AggMax:
+		return "max"
+	case AggMean:
+		return "mean"
+	}
+	return "sum"
+}
+
+// Aggregation is a column summarised by GroupBy.
+type Aggregation struct {
+	Header string
+	Func   AggFunc
+	// Name is the header of the summary column. Defaults to the header and function e.g. "AMOUNT_sum".
+	Name string
+}
+
+// name returns the header of the aggregation's summary column.
+func (agg Aggregation) name() string {
+	if agg.Name != "" {
+		return agg.Name
+	}
+	return agg.Header + "_" + agg.Func.String()
+}
+
+// SummaryDiagnostics counts the cells left out of the numeric summaries of GroupBy and Pivot
+// keyed by the header of the summarised column.
+type SummaryDiagnostics struct {
+	// NonNumeric counts the non blank cells whose decimal format is not a number.
+	NonNumeric map[string]int
+	// NonNumericCells holds the worksheet address of every non numeric cell read from a worksheet.
+	// Cells without a worksheet origin e.g. those of an AggregatedParse are only counted.
+	NonNumericCells map[string][]string
+	// Blank counts the blank cells.
+	Blank map[string]int
+}
+
+func makeSummaryDiagnostics() SummaryDiagnostics {
+	return SummaryDiagnostics{
+		NonNumeric:      make(map[string]int),
+		NonNumericCells: make(map[string][]string),
+		Blank:           make(map[string]int),
+	}
+}
+
+// HasIssues returns whether any cell was left out of a numeric summary.
+func (sd SummaryDiagnostics) HasIssues() bool {
+	return len(sd.NonNumeric) > 0 || len(sd.Blank) > 0
+}
+
+// summaryCell is a cell read for a summary.
+type summaryCell struct {
+	value             float64
+	nonBlank, numeric bool
+}
+
+// read reads the cell at the pair of indices and records it when it is not a number.
+func (sd SummaryDiagnostics) read(ps *ParsedSheet, r, c int) summaryCell {
+	header := ps.Original[0][c]
+	s := ps.DecimalFormat[r][c]
+	if strings.TrimSpace(s) == "" {
+		sd.Blank[header]++
+		return summaryCell{}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		sd.NonNumeric[header]++
+		if addr, err := ps.CellAddress(r, c); err == nil {
+			sd.NonNumericCells[header] = append(sd.NonNumericCells[header], addr)
+		}
+		return summaryCell{nonBlank: true}
+	}
+	return summaryCell{value: v, nonBlank: true, numeric: true}
+}
+
+// accumulator holds the running summaries of the values of a group.
+type accumulator struct {
+	nonBlank, n   int
+	sum, min, max float64
+}
+
+func (acc *accumulator) add(cell summaryCell) {
+	if cell.nonBlank {
+		acc.nonBlank++
+	}
+	if !cell.numeric {
+		return
+	}
+	v := cell.value
+	if acc.n == 0 || v < acc.min {
+		acc.min = v
+	}
+	if acc.n == 0 || v > acc.max {
+		acc.max = v
+	}
+	acc.n++
+	acc.sum += v
+}
+
+// value returns the summary of the function formatted as a decimal.
+//	Summaries other than count are blank when the group has no numbers.
+func (acc *accumulator) value(fn AggFunc) string {
+	if fn == AggCount {
+		return strconv.Itoa(acc.nonBlank)
+	}
+	if acc.n == 0 {
+		return ""
+	}
+	v := acc.sum
+	switch fn {
+	case AggMin:
+		v = acc.min
+	case AggMax:
+		v = acc.max
+	case AggMean:
+		v = acc.sum / float64(acc.n)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// summarySheet returns a ParsedSheet of summary rows with the same original and decimal formats.
+//	The summary has no worksheet origins or cells.
+func summarySheet(name string, header []string, originals [][]string, decimals [][]string) *ParsedSheet {
+	ps := &ParsedSheet{
+		Original:      [][]string{header},
+		DecimalFormat: [][]string{append([]string(nil), header...)},
+		Name:          name,
+	}
+	ps.Original = append(ps.Original, originals...)
+	ps.DecimalFormat = append(ps.DecimalFormat, decimals...)
+	ps.RowOrigins = make([]int, len(ps.Original))
+	ps.ColOrigins = make([]int, len(header))
+	return ps
+}
+
+// Grouping is the rows of a ParsedSheet grouped by key columns, see ParsedSheet.GroupBy.
+type Grouping struct {
+	sheet      *ParsedSheet
+	keyHeaders []string
+	keys       []int
+	err        error
+}
+
+// GroupBy groups the data rows by the decimal formatted values of the columns with the key headers.
+// The groups are summarised with Agg.
+func (ps *ParsedSheet) GroupBy(keyHeaders ...string) Grouping {
+	keys, err := ps.columnIndices(keyHeaders)
+	return Grouping{sheet: ps, keyHeaders: keyHeaders, keys: keys, err: err}
+}
+
+// GroupBy groups the items of the aggregation in the same way as ParsedSheet.GroupBy.
+//	The items have no worksheet origins so the diagnostics have no NonNumericCells.
+func (ap *AggregatedParse) GroupBy(keyHeaders ...string) Grouping {
+	return ap.ToParsedSheet().GroupBy(keyHeaders...)
+}
+
+// Agg returns a ParsedSheet with a row for each group holding its key values followed by
+// a column for each aggregation. Groups are ordered by their first row.
+// ErrDuplicateHeader is returned when two aggregations or an aggregation and a key share a header.
+//	Numbers are read from DecimalFormat and the cells which are not numbers are counted
+//	in the diagnostics rather than failing the summary.
+func (g Grouping) Agg(aggs ...Aggregation) (*ParsedSheet, SummaryDiagnostics, error) {
+	diag := makeSummaryDiagnostics()
+	if g.err != nil {
+		return nil, diag, g.err
+	}
+	ps := g.sheet
+	aggCols := make([]int, 0, len(aggs))
+	header := append([]string(nil), g.keyHeaders...)
+	for _, agg := range aggs {
+		c, err := ps.ColumnIndex(agg.Header)
+		if err != nil {
+			return nil, diag, err
+		}
+		aggCols = append(aggCols, c)
+		header = append(header, agg.name())
+	}
+	if err := uniqueHeaderErr(header); err != nil {
+		return nil, diag, err
+	}
+
+	order := make([]string, 0)
+	firstRows := make(map[string]int)
+	accs := make(map[string][]accumulator)
+	for r := 1; r < len(ps.DecimalFormat); r++ {
+		key := joinKey(ps.DecimalFormat[r], g.keys)
+		if _, ok := firstRows[key]; !ok {
+			order = append(order, key)
+			firstRows[key] = r
+			accs[key] = make([]accumulator, len(aggs))
+		}
+		// Each column is read once so diagnostics count every cell once.
+		cells := make(map[int]summaryCell, len(aggCols))
+		for i, c := range aggCols {
+			cell, ok := cells[c]
+			if !ok {
+				cell = diag.read(ps, r, c)
+				cells[c] = cell
+			}
+			accs[key][i].add(cell)
+		}
+	}
+	originals := make([][]string, 0, len(order))
+	decimals := make([][]string, 0, len(order))
+	for _, key := range order {
+		original := make([]string, 0, len(header))
+		decimal := make([]string, 0, len(header))
+		for _, c := range g.keys {
+			original = append(original, ps.Original[firstRows[key]][c])
+			decimal = append(decimal, ps.DecimalFormat[firstRows[key]][c])
+		}
+		for i, agg := range aggs {
+			v := accs[key][i].value(agg.Func)
+			original = append(original, v)
+			decimal = append(decimal, v)
+		}
+		originals = append(originals, original)
+		decimals = append(decimals, decimal)
+	}
+	return summarySheet(ps.Name, header, originals, decimals), diag, nil
+}
+
+// Pivot returns a ParsedSheet with a row for each distinct value of the row key column and a
+// column for each distinct value of the column key column, holding the summary of the value
+// column's cells in common. Rows and columns are ordered by first appearance.
+// The columns are headed by the originally formatted values, so ErrDuplicateHeader is returned
+// when a value is blank, equal to the row key or formatted the same as another value.
+//	Combinations without any rows are blank.
+//	Numbers are read from DecimalFormat and non numeric cells are counted in the diagnostics.
+func (ps *ParsedSheet) Pivot(rowKey, colKey, valueHeader string, fn AggFunc) (*ParsedSheet, SummaryDiagnostics, error) {
+	diag := makeSummaryDiagnostics()
+	indices, err := ps.columnIndices([]string{rowKey, colKey})
+	if err != nil {
+		return nil, diag, err
+	}
+	valueCol, err := ps.ColumnIndex(valueHeader)
+	if err != nil {
+		return nil, diag, err
+	}
+	rowCol, colCol := indices[0], indices[1]
+
+	rowOrder, colOrder := make([]string, 0), make([]string, 0)
+	rowFirst, colFirst := make(map[string]int), make(map[string]int)
+	accs := make(map[string]map[string]*accumulator)
+	for r := 1; r < len(ps.DecimalFormat); r++ {
+		rowVal, colVal := ps.DecimalFormat[r][rowCol], ps.DecimalFormat[r][colCol]
+		if _, ok := rowFirst[rowVal]; !ok {
+			rowOrder = append(rowOrder, rowVal)
+			rowFirst[rowVal] = r
+			accs[rowVal] = make(map[string]*accumulator)
+		}
+		if _, ok := colFirst[colVal]; !ok {
+			colOrder = append(colOrder, colVal)
+			colFirst[colVal] = r
+		}
+		acc, ok := accs[rowVal][colVal]
+		if !ok {
+			acc = &accumulator{}
+			accs[rowVal][colVal] = acc
+		}
+		acc.add(diag.read(ps, r, valueCol))
+	}
+
+	header := []string{rowKey}
+	seen := map[string]bool{rowKey: true}
+	for _, colVal := range colOrder {
+		h := ps.Original[colFirst[colVal]][colCol]
+		if strings.TrimSpace(h) == "" {
+			return nil, diag, fmt.Errorf("%w: blank %s value", ErrDuplicateHeader, colKey)
+		}
+		if seen[h] {
+			return nil, diag, fmt.Errorf("%w: %s", ErrDuplicateHeader, h)
+		}
+		seen[h] = true
+		header = append(header, h)
+	}
+	originals := make([][]string, 0, len(rowOrder))
+	decimals := make([][]string, 0, len(rowOrder))
+	for _, rowVal := range rowOrder {
+		original := []string{ps.Original[rowFirst[rowVal]][rowCol]}
+		decimal := []string{rowVal}
+		for _, colVal := range colOrder {
+			v := ""
+			if acc, ok := accs[rowVal][colVal]; ok {
+				v = acc.value(fn)
+			}
+			original = append(original, v)
+			decimal = append(decimal, v)
+		}
+		originals = append(originals, original)
+		decimals = append(decimals, decimal)
+	}
+	return summarySheet(ps.Name, header, originals, decimals), diag, nil
+}
+
+// Pivot summarises the items of the aggregation in the same way as ParsedSheet.Pivot.
+//	The items have no worksheet origins so the diagnostics have no NonNumericCells.
+func (ap *AggregatedParse) Pivot(rowKey, colKey, valueHeader string, fn AggFunc) (*ParsedSheet, SummaryDiagnostics, error) {
+	return ap.ToParsedSheet().Pivot(rowKey, colKey, valueHeader, fn)
+}
@@ -0,0 +1,110 @@
+package parse
+
+import (
+	"errors"
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrTimeText is returned when text can not be parsed with any of the given time layouts.
+var ErrTimeText = errors.New("sheetParse: text does not match any time layout")
+
+// DefaultTimeLayouts are the layouts tried, in order, when a time cell
+// is stored as text instead of an Excel serial number.
+var DefaultTimeLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"1/2/2006",
+	"1/2/2006 15:04",
+	"1/2/2006 15:04:05",
+	"1/2/2006 3:04 PM",
+	"1-2-2006",
+	"1-2-06",
+	"2-Jan-2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+// ParseTimeText attempts to parse text using each of the layouts in order.
+// Times without a zone are interpreted in loc.
+func ParseTimeText(s string, layouts []string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrTimeText
+}
+
+// inLocation keeps the wall clock of t but places it in loc.
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// excelEpoch1900 and excelEpoch1904 are the days which the serial number zero
+// represents in each of the workbook date systems.
+//	excelEpoch1900 is the 30th of December to account for Excel treating 1900 as a leap year.
+var (
+	excelEpoch1900        = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	excelEpoch1904        = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	excelLeapBugPeriodEnd = time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC)
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// WorkbookDate1904 returns whether a workbook uses the 1904 date system
+// which is common for workbooks created on older versions of Excel for Mac.
+func WorkbookDate1904(f *excelize.File) bool {
+	return f.WorkBook != nil && f.WorkBook.WorkbookPr != nil && f.WorkBook.WorkbookPr.Date1904
+}
+
+// TimeToExcelDate converts the wall clock of t to an Excel serial number
+// in the given date system.
+func TimeToExcelDate(t time.Time, date1904 bool) float64 {
+	wall := inLocation(t, time.UTC)
+	epoch := excelEpoch1900
+	if date1904 {
+		epoch = excelEpoch1904
+	}
+	seconds := wall.Unix() - epoch.Unix()
+	serial := float64(seconds)/secondsPerDay + float64(wall.Nanosecond())/(secondsPerDay*float64(time.Second))
+	if !date1904 && wall.Before(excelLeapBugPeriodEnd) {
+		serial--
+	}
+	return serial
+}
+
+// ParseTimeCell attempts to parse a cell as a time.Time using its decimal
+// formatted and originally formatted values.
+// The Excel serial number is used when possible, otherwise the originally
+// formatted text is parsed with each of the layouts.
+func ParseTimeCell(decimal, original string, date1904 bool, layouts []string, loc *time.Location) (time.Time, error) {
+	f, err := strconv.ParseFloat(decimal, 64)
+	if err != nil {
+		return ParseTimeText(original, layouts, loc)
+	}
+	t, err := excelize.ExcelDateToTime(f, date1904)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return inLocation(t, loc), nil
+}
+
+// ParsedTimeIn attempts to parse the cell value as a time.Time in the given location
+// using the sheet's date system.
+// Excel serial numbers are used when possible, otherwise the originally formatted
+// text is parsed with each of the layouts.
+func (ps *ParsedSheet) ParsedTimeIn(r, c int, layouts []string, loc *time.Location) (time.Time, error) {
+	if err := ps.indexErr(r, c); err != nil {
+		return time.Time{}, err
+	}
+	if err := ps.cellErr(r, c); err != nil {
+		return time.Time{}, err
+	}
+	return ParseTimeCell(ps.DecimalFormat[r][c], ps.Original[r][c], ps.Date1904, layouts, loc)
+}
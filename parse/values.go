@@ -0,0 +1,110 @@
+package parse
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrBoolText is returned when text can not be read as a boolean.
+var ErrBoolText = errors.New("sheetParse: text is not a boolean")
+
+// ErrDurationText is returned when text can not be read as a duration.
+var ErrDurationText = errors.New("sheetParse: text is not a duration")
+
+// ErrDecimalText is returned when text can not be read as an exact decimal.
+var ErrDecimalText = errors.New("sheetParse: text is not a decimal")
+
+// boolTexts maps the lower case texts which can be read as booleans to their values.
+var boolTexts = map[string]bool{
+	"true":  true,
+	"false": false,
+	"t":     true,
+	"f":     false,
+	"yes":   true,
+	"no":    false,
+	"y":     true,
+	"n":     false,
+	"1":     true,
+	"0":     false,
+}
+
+// ParseBoolText reads TRUE/FALSE, Y/N, yes/no and 1/0 text as a boolean ignoring case.
+func ParseBoolText(s string) (bool, error) {
+	b, ok := boolTexts[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return false, ErrBoolText
+	}
+	return b, nil
+}
+
+// ParseDurationText reads "hh:mm" and "hh:mm:ss" text as a duration.
+// Hours may exceed 24, seconds may have a fraction and a leading minus is allowed.
+func ParseDurationText(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, ErrDurationText
+	}
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v < 0 || (i < len(parts)-1 && strings.Contains(part, ".")) {
+			return 0, ErrDurationText
+		}
+		d += time.Duration(v * float64(units[i]))
+	}
+	if negative {
+		d = -d
+	}
+	return d, nil
+}
+
+// excelDayDuration is the duration which the serial number 1 represents.
+const excelDayDuration = 24 * time.Hour
+
+// ParsedBool attempts to parse the originally formatted cell value as a boolean.
+func (ps *ParsedSheet) ParsedBool(r, c int) (bool, error) {
+	if err := ps.indexErr(r, c); err != nil {
+		return false, err
+	}
+	if err := ps.cellErr(r, c); err != nil {
+		return false, err
+	}
+	return ParseBoolText(ps.Original[r][c])
+}
+
+// ParsedDuration attempts to parse the cell value as a time.Duration.
+// Excel times are fractions of a day, otherwise the originally formatted text
+// is read as "hh:mm:ss".
+func (ps *ParsedSheet) ParsedDuration(r, c int) (time.Duration, error) {
+	f, err := ps.ParsedFloat(r, c)
+	if err == nil {
+		return time.Duration(f * float64(excelDayDuration)).Round(time.Millisecond), nil
+	}
+	if err == ErrInvalidIndices || errors.Is(err, ErrCellError) {
+		return 0, err
+	}
+	return ParseDurationText(ps.Original[r][c])
+}
+
+// ParsedRat attempts to parse the decimal formatted cell value as an exact *big.Rat.
+// Unlike ParsedFloat there is no float64 rounding e.g. cents stay exact.
+func (ps *ParsedSheet) ParsedRat(r, c int) (*big.Rat, error) {
+	if err := ps.indexErr(r, c); err != nil {
+		return nil, err
+	}
+	if err := ps.cellErr(r, c); err != nil {
+		return nil, err
+	}
+	rat, ok := new(big.Rat).SetString(strings.TrimSpace(ps.DecimalFormat[r][c]))
+	if !ok {
+		return nil, ErrDecimalText
+	}
+	return rat, nil
+}
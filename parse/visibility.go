@@ -0,0 +1,112 @@
+package parse
+
+import (
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+// SheetVisibility is the visibility state of a sheet.
+type SheetVisibility int
+
+const (
+	SheetVisible SheetVisibility = iota
+	// SheetHidden sheets can be unhidden by the user.
+	SheetHidden
+	// SheetVeryHidden sheets can only be unhidden with VBA and usually hold helper data.
+	SheetVeryHidden
+)
+
+func (sv SheetVisibility) String() string {
+	switch sv {
+	case SheetHidden:
+		return "hidden"
+	case SheetVeryHidden:
+		return "veryHidden"
+	}
+	return "visible"
+}
+
+// sheetVisibility returns the visibility state of a sheet.
+func sheetVisibility(f *excelize.File, sheet string) SheetVisibility {
+	if f.WorkBook == nil {
+		return SheetVisible
+	}
+	for _, s := range f.WorkBook.Sheets.Sheet {
+		if s.Name != sheet {
+			continue
+		}
+		switch s.State {
+		case "hidden":
+			return SheetHidden
+		case "veryHidden":
+			return SheetVeryHidden
+		}
+	}
+	return SheetVisible
+}
+
+// recordHidden records the hidden rows and columns of the data.
+func (ps *ParsedSheet) recordHidden(f *excelize.File) error {
+	wsPath, err := loadWorksheet(f, ps.Name)
+	if err != nil {
+		return err
+	}
+	ws := f.Sheet[wsPath]
+	hiddenRows := make([]int, 0)
+	for _, row := range ws.SheetData.Row {
+		if row.Hidden {
+			hiddenRows = append(hiddenRows, row.R)
+		}
+	}
+	ps.HiddenRows = keepOrigins(hiddenRows, ps.rowOrigins())
+	hiddenColumns := make([]int, 0)
+	if ws.Cols != nil {
+		colOrigins := ps.colOrigins()
+		maxCol := 0
+		for _, c := range colOrigins {
+			if c > maxCol {
+				maxCol = c
+			}
+		}
+		for _, col := range ws.Cols.Col {
+			if !col.Hidden {
+				continue
+			}
+			for c := col.Min; c <= col.Max && c <= maxCol; c++ {
+				hiddenColumns = append(hiddenColumns, c)
+			}
+		}
+	}
+	ps.HiddenColumns = keepOrigins(hiddenColumns, ps.colOrigins())
+	return nil
+}
+
+// skipHidden removes the hidden rows and columns recorded by recordHidden when the options skip them.
+//	The header row is never removed.
+func (ps *ParsedSheet) skipHidden(opts ParseOptions) {
+	if opts.SkipHiddenRows && len(ps.HiddenRows) > 0 {
+		hidden := make(map[int]bool)
+		for _, r := range ps.HiddenRows {
+			hidden[r] = true
+		}
+		remove := make(map[int]bool)
+		for r, origin := range ps.rowOrigins() {
+			if r > 0 && hidden[origin] {
+				remove[r] = true
+			}
+		}
+		ps.removeRows(remove)
+	}
+	if opts.SkipHiddenColumns && len(ps.HiddenColumns) > 0 {
+		hidden := make(map[int]bool)
+		for _, c := range ps.HiddenColumns {
+			hidden[c] = true
+		}
+		remove := make(map[int]bool)
+		for c, origin := range ps.colOrigins() {
+			if hidden[origin] {
+				remove[c] = true
+			}
+		}
+		ps.removeColumns(remove)
+	}
+}
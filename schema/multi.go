@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"path/filepath"
+	"reflect"
+)
+
+// Functions to apply a schema to many sheets and files at once
+// while collecting every row into a single slice.
+
+// SheetFilter decides whether a sheet of a workbook should have the schema applied.
+//	idx is the zero based position of the sheet in the workbook.
+type SheetFilter func(idx int, name string) bool
+
+// AllSheets returns a SheetFilter which selects every sheet.
+func AllSheets() SheetFilter {
+	return func(int, string) bool {
+		return true
+	}
+}
+
+// SheetNameMatch returns a SheetFilter which selects sheets whose name
+// matches the given filepath.Match pattern e.g. "Branch *".
+func SheetNameMatch(pattern string) SheetFilter {
+	return func(_ int, name string) bool {
+		matched, err := filepath.Match(pattern, name)
+		return err == nil && matched
+	}
+}
+
+// SheetIndices returns a SheetFilter which selects sheets by position.
+//	Note: indices are zero based
+func SheetIndices(indices ...int) SheetFilter {
+	idxMap := make(map[int]bool)
+	for _, idx := range indices {
+		idxMap[idx] = true
+	}
+	return func(idx int, _ string) bool {
+		return idxMap[idx]
+	}
+}
+
+// FailedSheet describes a sheet which could not have the schema applied.
+// Sheet is empty when the file itself could not be opened.
+type FailedSheet struct {
+	Path  string
+	Sheet string
+	Err   error
+}
+
+// MultiApplyResult is the result of applying a schema to many sheets.
+type MultiApplyResult struct {
+	// AppliedSheets holds the number of sheets successfully applied.
+	AppliedSheets int
+	FailedSheets  []FailedSheet
+}
+
+// ApplySchemaToSheets applies the schema to every sheet selected by filter and
+// appends all the rows to v (pointer to slice of tagged structs).
+// A sheet which fails is recorded in the result and none of its rows are appended.
+func (sc Schema) ApplySchemaToSheets(filter SheetFilter, v interface{}) (MultiApplyResult, error) {
+	vSlice := reflect.ValueOf(v).Elem()
+	if !typeIsStructSlice(vSlice) {
+		return MultiApplyResult{}, ErrNotStructSlice
+	}
+	result := MultiApplyResult{FailedSheets: make([]FailedSheet, 0)}
+	for idx, sheet := range sc.f.GetSheetList() {
+		if !filter(idx, sheet) {
+			continue
+		}
+		sheetSlicePtr := reflect.New(vSlice.Type())
+		if err := sc.ApplySchema(sheet, sheetSlicePtr.Interface()); err != nil {
+			result.FailedSheets = append(result.FailedSheets, FailedSheet{
+				Path:  sc.path,
+				Sheet: sheet,
+				Err:   err,
+			})
+			continue
+		}
+		vSlice.Set(reflect.AppendSlice(vSlice, sheetSlicePtr.Elem()))
+		result.AppliedSheets++
+	}
+	return result, nil
+}
+
+// ApplySchemaToFiles applies the schema to every sheet selected by filter in every
+// file matching the filepath.Glob pattern and appends all the rows to v.
+//	Use provenance tags (-file, -sheet, -row) to know where each row came from.
+func ApplySchemaToFiles(pattern string, filter SheetFilter, v interface{}) (MultiApplyResult, error) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return MultiApplyResult{}, err
+	}
+	if !typeIsStructSlice(reflect.ValueOf(v).Elem()) {
+		return MultiApplyResult{}, ErrNotStructSlice
+	}
+	result := MultiApplyResult{FailedSheets: make([]FailedSheet, 0)}
+	for _, path := range paths {
+		sch, err := MakeSchema(path)
+		if err != nil {
+			result.FailedSheets = append(result.FailedSheets, FailedSheet{Path: path, Err: err})
+			continue
+		}
+		fileResult, err := sch.ApplySchemaToSheets(filter, v)
+		if err != nil {
+			return result, err
+		}
+		result.AppliedSheets += fileResult.AppliedSheets
+		result.FailedSheets = append(result.FailedSheets, fileResult.FailedSheets...)
+	}
+	return result, nil
+}
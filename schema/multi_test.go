@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestApplySchemaToFiles(t *testing.T) {
+	var idArr []IdDataWithProvenance
+	res, err := ApplySchemaToFiles(filepath.Join("data", "data*.xlsx"), AllSheets(), &idArr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AppliedSheets != 1 || len(res.FailedSheets) != 0 {
+		t.Error("STRING_ID should be applied without failures", res)
+	}
+	if len(idArr) != 9 {
+		t.Error("STRING_ID should provide 9 rows, provided", len(idArr))
+	}
+	for _, v := range idArr {
+		if v.Sheet != "STRING_ID" {
+			t.Error("every row should come from STRING_ID, got", v.Sheet)
+		}
+	}
+}
+
+type missingHeader struct {
+	Missing StringField `gxl:"NOT_A_HEADER"`
+}
+
+func TestSchema_ApplySchemaToSheetsFilter(t *testing.T) {
+	s, err := MakeSchema(filepath.Join("data", "data.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var byName []IdData
+	res, err := s.ApplySchemaToSheets(SheetNameMatch("STRING_*"), &byName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AppliedSheets != 1 || len(byName) != 9 {
+		t.Error("name filter should select STRING_ID", res)
+	}
+	var none []IdData
+	res, err = s.ApplySchemaToSheets(SheetIndices(1), &none)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AppliedSheets != 0 || len(none) != 0 {
+		t.Error("index filter should not select any sheet", res)
+	}
+	var missing []missingHeader
+	res, err = s.ApplySchemaToSheets(SheetIndices(0), &missing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.FailedSheets) != 1 || res.FailedSheets[0].Err != ErrTaggedHeaderDNEInData {
+		t.Error("STRING_ID should fail with a missing header", res)
+	}
+}
@@ -0,0 +1,364 @@
+package schema
+
+import (
+	"errors"
+	"github.com/C-Canchola/goexcel/parse"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tag options follow the header of a tag separated by commas.
+//	e.g. gxl:"Date,layout=01/02/2006|2006-01-02,tz=America/Chicago"
+//	A value containing commas is wrapped in single quotes and a single quote
+//	within it is written twice e.g. gxl:"Date,layout='Jan 2, 2006|2006-01-02'".
+const (
+	tagOptionSeparator      = ","
+	tagOptionValueSeparator = "="
+	tagOptionListSeparator  = "|"
+	tagOptionQuote          = "'"
+)
+
+// Supported tag options.
+//	layout is a list of time layouts tried when a TimeField cell is text.
+//	tz is the IANA time zone a TimeField is interpreted in.
+//	number reads FloatField, IntField and DecimalField cells stored as formatted text e.g. "$1,234.50".
+//	Its value is the locale of the text, either us (default) or eu.
+//	blank decides how a blank cell is treated, either error or zero.
+//	default is the value used for a blank cell e.g. gxl:"Units,default=1". Quote it to include commas.
+//	link decodes a StringField as the target of the cell's hyperlink instead of its text e.g. gxl:"Ticket,link".
+//	style sets a BoolField when the cell's style matches any of the listed predicates, see stylePredicates.
+//	e.g. gxl:"Ticket,style=fill:FFFF00|strike". Style fields may share their header with another field.
+const (
+	layoutTagOption  = "layout"
+	tzTagOption      = "tz"
+	numberTagOption  = "number"
+	blankTagOption   = "blank"
+	defaultTagOption = "default"
+	linkTagOption    = "link"
+	styleTagOption   = "style"
+)
+
+// blankMode decides how a blank cell is treated.
+type blankMode int
+
+const (
+	// blankError treats a blank cell as unsuccessful. Default for all but StringField.
+	blankError blankMode = iota
+	// blankZero treats a blank cell as a successful zero value. Default for StringField.
+	blankZero
+	// blankDefault treats a blank cell as the default tag option's value.
+	blankDefault
+)
+
+// blankModes maps the values of the blank tag option to their modes.
+var blankModes = map[string]blankMode{
+	"error": blankError,
+	"zero":  blankZero,
+}
+
+// numberLocales maps the values of the number tag option to their locales.
+var numberLocales = map[string]parse.NumberLocale{
+	"":   parse.LocaleUS,
+	"us": parse.LocaleUS,
+	"eu": parse.LocaleEU,
+}
+
+var ErrInvalidTagOption = errors.New("schema: tag option is unknown or invalid for the field type")
+
+// stylePredicates maps the predicates of the style tag option to their functions.
+// Predicates ending in ":" are followed by an RGB hex color e.g. fill:FFFF00.
+var stylePredicates = map[string]func(cs parse.CellStyle, color string) bool{
+	"fill": func(cs parse.CellStyle, _ string) bool { return cs.IsFilled() },
+	"fill:": func(cs parse.CellStyle, color string) bool {
+		return strings.EqualFold(cs.FillColor, color)
+	},
+	"font:": func(cs parse.CellStyle, color string) bool {
+		return strings.EqualFold(cs.FontColor, color)
+	},
+	"bold":   func(cs parse.CellStyle, _ string) bool { return cs.Bold },
+	"italic": func(cs parse.CellStyle, _ string) bool { return cs.Italic },
+	"strike": func(cs parse.CellStyle, _ string) bool { return cs.Strike },
+}
+
+// parseStyleOption returns a function which reports whether a cell style
+// matches any of the predicates of a style tag option.
+func parseStyleOption(value string) (func(parse.CellStyle) bool, error) {
+	type predicate struct {
+		fn    func(parse.CellStyle, string) bool
+		color string
+	}
+	predicates := make([]predicate, 0)
+	for _, p := range strings.Split(value, tagOptionListSeparator) {
+		p = strings.TrimSpace(p)
+		key, color := p, ""
+		if idx := strings.Index(p, ":"); idx >= 0 {
+			key, color = p[:idx+1], strings.TrimPrefix(p[idx+1:], "#")
+			if color == "" {
+				return nil, ErrInvalidTagOption
+			}
+		}
+		fn, ok := stylePredicates[key]
+		if !ok {
+			return nil, ErrInvalidTagOption
+		}
+		predicates = append(predicates, predicate{fn: fn, color: color})
+	}
+	return func(cs parse.CellStyle) bool {
+		for _, p := range predicates {
+			if p.fn(cs, p.color) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// splitTag splits a tag value on the option separator except within quoted option values.
+func splitTag(tag string) []string {
+	parts := make([]string, 0)
+	var part strings.Builder
+	inQuote := false
+	for i := 0; i < len(tag); i++ {
+		ch := tag[i : i+1]
+		switch {
+		case inQuote && ch == tagOptionQuote:
+			if i+1 < len(tag) && tag[i+1:i+2] == tagOptionQuote {
+				part.WriteString(ch)
+				i++
+				continue
+			}
+			inQuote = false
+		case !inQuote && ch == tagOptionQuote && len(parts) > 0 &&
+			strings.HasSuffix(strings.TrimSpace(part.String()), tagOptionValueSeparator):
+			// Quotes are only special at the start of an option value so headers may contain them.
+			inQuote = true
+		case !inQuote && ch == tagOptionSeparator:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteString(ch)
+		}
+	}
+	return append(parts, part.String())
+}
+
+// parseTag splits a tag value into its header and options.
+func parseTag(tag string) (string, map[string]string) {
+	parts := splitTag(tag)
+	options := make(map[string]string)
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, tagOptionValueSeparator, 2)
+		key := strings.TrimSpace(kv[0])
+		if len(kv) == 1 {
+			options[key] = ""
+			continue
+		}
+		options[key] = strings.TrimSpace(kv[1])
+	}
+	return parts[0], options
+}
+
+// tagHeader returns the header portion of a tag value.
+func tagHeader(tag string) string {
+	header, _ := parseTag(tag)
+	return header
+}
+
+// fieldOptions holds the parsed tag options of a single field.
+type fieldOptions struct {
+	timeLayouts []string
+	location    *time.Location
+	// numberLocale is nil unless formatted text should be read as a number.
+	numberLocale *parse.NumberLocale
+	blank        blankMode
+	defaultValue string
+	// link is true when a StringField holds the cell's hyperlink target.
+	link bool
+}
+
+func (fo fieldOptions) hasDefault() bool {
+	return fo.blank == blankDefault
+}
+
+// blankTime returns the value and success of a blank TimeField cell.
+func (fo fieldOptions) blankTime() (time.Time, bool) {
+	switch fo.blank {
+	case blankZero:
+		return time.Time{}, true
+	case blankDefault:
+		t, err := parse.ParseTimeText(fo.defaultValue, fo.timeLayouts, fo.location)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// blankFloat returns the value and success of a blank FloatField or IntField cell.
+func (fo fieldOptions) blankFloat() (float64, bool) {
+	switch fo.blank {
+	case blankZero:
+		return 0, true
+	case blankDefault:
+		f, err := strconv.ParseFloat(fo.defaultValue, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// blankBool returns the value and success of a blank BoolField cell.
+func (fo fieldOptions) blankBool() (bool, bool) {
+	switch fo.blank {
+	case blankZero:
+		return false, true
+	case blankDefault:
+		b, err := parse.ParseBoolText(fo.defaultValue)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// blankDuration returns the value and success of a blank DurationField cell.
+func (fo fieldOptions) blankDuration() (time.Duration, bool) {
+	switch fo.blank {
+	case blankZero:
+		return 0, true
+	case blankDefault:
+		d, err := parse.ParseDurationText(fo.defaultValue)
+		return d, err == nil
+	}
+	return 0, false
+}
+
+// blankRat returns the value and success of a blank DecimalField cell.
+func (fo fieldOptions) blankRat() (*big.Rat, bool) {
+	switch fo.blank {
+	case blankZero:
+		return new(big.Rat), true
+	case blankDefault:
+		return new(big.Rat).SetString(fo.defaultValue)
+	}
+	return nil, false
+}
+
+// blankString returns the value and success of a blank StringField cell.
+func (fo fieldOptions) blankString() (string, bool) {
+	switch fo.blank {
+	case blankZero:
+		return "", true
+	case blankDefault:
+		return fo.defaultValue, true
+	}
+	return "", false
+}
+
+// makeFieldOptions validates and parses the tag options for a field of the given type.
+//	Pointer field types use the options of the type they point to.
+func makeFieldOptions(t reflect.Type, options map[string]string) (fieldOptions, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	fo := fieldOptions{
+		timeLayouts: parse.DefaultTimeLayouts,
+		location:    time.UTC,
+	}
+	if t == reflect.TypeOf(StringField{}) {
+		fo.blank = blankZero
+	}
+	for key, value := range options {
+		switch key {
+		case layoutTagOption:
+			if t != reflect.TypeOf(TimeField{}) || value == "" {
+				return fieldOptions{}, ErrInvalidTagOption
+			}
+			fo.timeLayouts = strings.Split(value, tagOptionListSeparator)
+		case tzTagOption:
+			if t != reflect.TypeOf(TimeField{}) {
+				return fieldOptions{}, ErrInvalidTagOption
+			}
+			loc, err := time.LoadLocation(value)
+			if err != nil {
+				return fieldOptions{}, err
+			}
+			fo.location = loc
+		case numberTagOption:
+			numberLocale, ok := numberLocales[value]
+			if !ok || (t != reflect.TypeOf(FloatField{}) && t != reflect.TypeOf(IntField{}) && t != reflect.TypeOf(DecimalField{})) {
+				return fieldOptions{}, ErrInvalidTagOption
+			}
+			fo.numberLocale = &numberLocale
+		case blankTagOption:
+			mode, ok := blankModes[value]
+			if !ok {
+				return fieldOptions{}, ErrInvalidTagOption
+			}
+			if _, hasDefault := options[defaultTagOption]; hasDefault {
+				return fieldOptions{}, ErrInvalidTagOption
+			}
+			fo.blank = mode
+		case defaultTagOption:
+			fo.defaultValue = value
+		case linkTagOption:
+			if t != reflect.TypeOf(StringField{}) || value != "" {
+				return fieldOptions{}, ErrInvalidTagOption
+			}
+			fo.link = true
+		default:
+			return fieldOptions{}, ErrInvalidTagOption
+		}
+	}
+	if _, hasDefault := options[defaultTagOption]; hasDefault {
+		fo.blank = blankDefault
+		if !fo.defaultIsValid(t) {
+			return fieldOptions{}, ErrInvalidTagOption
+		}
+	}
+	return fo, nil
+}
+
+// defaultIsValid returns whether the default value can be parsed as the field type.
+func (fo fieldOptions) defaultIsValid(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeOf(TimeField{}):
+		_, ok := fo.blankTime()
+		return ok
+	case reflect.TypeOf(FloatField{}):
+		_, ok := fo.blankFloat()
+		return ok
+	case reflect.TypeOf(IntField{}):
+		// Int defaults must be whole numbers rather than being truncated.
+		_, err := strconv.Atoi(fo.defaultValue)
+		return err == nil
+	case reflect.TypeOf(BoolField{}):
+		_, ok := fo.blankBool()
+		return ok
+	case reflect.TypeOf(DurationField{}):
+		_, ok := fo.blankDuration()
+		return ok
+	case reflect.TypeOf(DecimalField{}):
+		_, ok := fo.blankRat()
+		return ok
+	}
+	return true
+}
+
+// taggedFieldOptionMap returns a map of the indices of schema tagged fields
+// with their parsed tag options.
+func taggedFieldOptionMap(v reflect.Value, taggedFieldMap map[string]int) (map[int]fieldOptions, error) {
+	t := v.Type()
+	if t.Kind() != reflect.Struct {
+		return nil, ErrNotStructType
+	}
+	m := make(map[int]fieldOptions)
+	for _, i := range taggedFieldMap {
+		field := t.Field(i)
+		_, options := parseTag(field.Tag.Get(TagKey))
+		fo, err := makeFieldOptions(field.Type, options)
+		if err != nil {
+			return nil, err
+		}
+		m[i] = fo
+	}
+	return m, nil
+}
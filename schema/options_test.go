@@ -0,0 +1,337 @@
+package schema
+
+import (
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/C-Canchola/goexcel/parse"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeTestWorkbook saves the rows to the first sheet of a new workbook
+// and returns the path of the file.
+func writeTestWorkbook(t *testing.T, sheet string, rows [][]interface{}) string {
+	f := excelize.NewFile()
+	f.SetSheetName(f.GetSheetName(0), sheet)
+	for rowIdx, row := range rows {
+		addr, _ := excelize.CoordinatesToCellName(1, rowIdx+1)
+		if err := f.SetSheetRow(sheet, addr, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "test.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseTag(t *testing.T) {
+	header, options := parseTag("Date,layout=01/02/2006,tz=America/Chicago")
+	if header != "Date" {
+		t.Error("header should be Date, is", header)
+	}
+	if options[layoutTagOption] != "01/02/2006" || options[tzTagOption] != "America/Chicago" {
+		t.Error("unexpected options", options)
+	}
+	if _, err := makeFieldOptions(reflect.TypeOf(StringField{}), options); err != ErrInvalidTagOption {
+		t.Error("layout should be invalid on a StringField, got", err)
+	}
+	header, options = parseTag("Owner's Date,layout='Jan 2, 2006|2006-01-02',default='It''s, here'")
+	if header != "Owner's Date" {
+		t.Error("header should keep its quote, is", header)
+	}
+	if options[layoutTagOption] != "Jan 2, 2006|2006-01-02" || options[defaultTagOption] != "It's, here" {
+		t.Error("quoted values should keep their commas, got", options)
+	}
+}
+
+type textDates struct {
+	Default TimeField `gxl:"DEFAULT"`
+	Layout  TimeField `gxl:"LAYOUT,layout=02.01.2006"`
+	Zoned   TimeField `gxl:"ZONED,layout=01/02/2006 15:04,tz=America/Chicago"`
+	Comma   TimeField `gxl:"COMMA,layout='Jan 2, 2006'"`
+}
+
+func TestTimeFieldTextLayouts(t *testing.T) {
+	path := writeTestWorkbook(t, "DATES", [][]interface{}{
+		{"DEFAULT", "LAYOUT", "ZONED", "COMMA"},
+		{"2020-03-01", "01.03.2020", "03/01/2020 13:45", "Mar 1, 2020"},
+	})
+	var arr []textDates
+	if err := MakeAndApplySchema(path, "DATES", &arr); err != nil {
+		t.Fatal(err)
+	}
+	if len(arr) != 1 {
+		t.Fatal("expected 1 row, got", len(arr))
+	}
+	want := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	if !arr[0].Default.Successful || !arr[0].Default.ParsedValue.Equal(want) {
+		t.Error("default layouts should parse 2020-03-01", arr[0].Default)
+	}
+	if !arr[0].Layout.Successful || !arr[0].Layout.ParsedValue.Equal(want) {
+		t.Error("layout option should parse 01.03.2020", arr[0].Layout)
+	}
+	if !arr[0].Comma.Successful || !arr[0].Comma.ParsedValue.Equal(want) {
+		t.Error("quoted layout option should parse Mar 1, 2020", arr[0].Comma)
+	}
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	wantZoned := time.Date(2020, 3, 1, 13, 45, 0, 0, chicago)
+	if !arr[0].Zoned.Successful || !arr[0].Zoned.ParsedValue.Equal(wantZoned) {
+		t.Error("tz option should place the time in America/Chicago", arr[0].Zoned)
+	}
+}
+
+type formattedNumbers struct {
+	Plain    FloatField `gxl:"AMOUNT"`
+	US       FloatField `gxl:"US,number"`
+	EU       FloatField `gxl:"EU,number=eu"`
+	Negative IntField   `gxl:"NEGATIVE,number=us"`
+}
+
+func TestNumberTagOption(t *testing.T) {
+	path := writeTestWorkbook(t, "NUMBERS", [][]interface{}{
+		{"AMOUNT", "US", "EU", "NEGATIVE"},
+		{"$1,234.50", "$1,234.50", "1.234,56 €", "(300)"},
+	})
+	var arr []formattedNumbers
+	if err := MakeAndApplySchema(path, "NUMBERS", &arr); err != nil {
+		t.Fatal(err)
+	}
+	row := arr[0]
+	if row.Plain.Successful {
+		t.Error("formatted text should not parse without the number option")
+	}
+	if !row.US.Successful || row.US.ParsedValue != 1234.5 {
+		t.Error("US should parse as 1234.5", row.US)
+	}
+	if !row.EU.Successful || row.EU.ParsedValue != 1234.56 {
+		t.Error("EU should parse as 1234.56", row.EU)
+	}
+	if !row.Negative.Successful || row.Negative.ParsedValue != -300 {
+		t.Error("NEGATIVE should parse as -300", row.Negative)
+	}
+}
+
+type blankValues struct {
+	Id       StringField `gxl:"ID"`
+	Error    FloatField  `gxl:"ERROR"`
+	Zero     FloatField  `gxl:"ZERO,blank=zero"`
+	Default  IntField    `gxl:"DEFAULT,default=7"`
+	Pointer  *FloatField `gxl:"POINTER"`
+	Reported *IntField   `gxl:"REPORTED"`
+	// Spaces fields hold whitespace which is text rather than blank.
+	Spaces        StringField  `gxl:"SPACES"`
+	SpacesPointer *StringField `gxl:"SPACES_POINTER"`
+}
+
+func TestBlankTagOptions(t *testing.T) {
+	path := writeTestWorkbook(t, "BLANKS", [][]interface{}{
+		{"ID", "ERROR", "ZERO", "DEFAULT", "POINTER", "REPORTED", "SPACES", "SPACES_POINTER"},
+		{"a", "", "", "", "", 0, "  ", " "},
+	})
+	var arr []blankValues
+	if err := MakeAndApplySchema(path, "BLANKS", &arr); err != nil {
+		t.Fatal(err)
+	}
+	row := arr[0]
+	if !row.Error.Empty || row.Error.Successful {
+		t.Error("blank cells should be empty and unsuccessful by default", row.Error)
+	}
+	if !row.Zero.Empty || !row.Zero.Successful || row.Zero.ParsedValue != 0 {
+		t.Error("blank=zero should be a successful zero", row.Zero)
+	}
+	if !row.Default.Empty || !row.Default.Successful || row.Default.ParsedValue != 7 {
+		t.Error("default=7 should be a successful 7", row.Default)
+	}
+	if row.Pointer != nil {
+		t.Error("blank pointer fields should be nil", row.Pointer)
+	}
+	if row.Reported == nil || row.Reported.Empty || row.Reported.ParsedValue != 0 {
+		t.Error("a reported zero should not be nil or empty", row.Reported)
+	}
+	if row.Id.Empty || !row.Id.Successful {
+		t.Error("ID should not be empty", row.Id)
+	}
+	if row.Spaces.Empty || !row.Spaces.Successful || row.Spaces.ParsedValue != "  " {
+		t.Errorf("whitespace should decode as text, got %+v", row.Spaces)
+	}
+	if row.SpacesPointer == nil || row.SpacesPointer.ParsedValue != " " {
+		t.Error("whitespace pointer fields should not be nil", row.SpacesPointer)
+	}
+}
+
+type invalidDefault struct {
+	Amount FloatField `gxl:"AMOUNT,default=abc"`
+}
+
+func TestInvalidDefaultTagOption(t *testing.T) {
+	var arr []invalidDefault
+	err := MakeAndApplySchema(filepath.Join("data", "data.xlsx"), "STRING_ID", &arr)
+	if err != ErrInvalidTagOption {
+		t.Error("expected ErrInvalidTagOption, got", err)
+	}
+	_, options := parseTag("UNITS,default=3.7")
+	if _, err := makeFieldOptions(reflect.TypeOf(IntField{}), options); err != ErrInvalidTagOption {
+		t.Error("a non integer default should be invalid on an IntField, got", err)
+	}
+	if _, err := makeFieldOptions(reflect.TypeOf(FloatField{}), options); err != nil {
+		t.Error("a decimal default should be valid on a FloatField, got", err)
+	}
+}
+
+type moreFieldTypes struct {
+	Flag     BoolField     `gxl:"FLAG"`
+	YesNo    BoolField     `gxl:"YES_NO"`
+	Time     DurationField `gxl:"TIME"`
+	Elapsed  DurationField `gxl:"ELAPSED"`
+	Amount   DecimalField  `gxl:"AMOUNT"`
+	Currency DecimalField  `gxl:"CURRENCY,number"`
+}
+
+func TestBoolDurationDecimalFields(t *testing.T) {
+	path := writeTestWorkbook(t, "TYPES", [][]interface{}{
+		{"FLAG", "YES_NO", "TIME", "ELAPSED", "AMOUNT", "CURRENCY"},
+		{true, "Y", 0.0625, "25:30:15", 0.1 + 0.2, "$1,234.56"},
+	})
+	var arr []moreFieldTypes
+	if err := MakeAndApplySchema(path, "TYPES", &arr); err != nil {
+		t.Fatal(err)
+	}
+	row := arr[0]
+	if !row.Flag.Successful || !row.Flag.ParsedValue {
+		t.Error("FLAG should be true", row.Flag)
+	}
+	if !row.YesNo.Successful || !row.YesNo.ParsedValue {
+		t.Error("YES_NO should be true", row.YesNo)
+	}
+	if !row.Time.Successful || row.Time.ParsedValue != 90*time.Minute {
+		t.Error("TIME should be 1h30m", row.Time)
+	}
+	if want := 25*time.Hour + 30*time.Minute + 15*time.Second; !row.Elapsed.Successful || row.Elapsed.ParsedValue != want {
+		t.Error("ELAPSED should be", want, row.Elapsed)
+	}
+	if !row.Amount.Successful || row.Amount.ParsedValue.Cmp(big.NewRat(3, 10)) != 0 {
+		t.Error("AMOUNT should be exactly 0.3", row.Amount)
+	}
+	if !row.Currency.Successful || row.Currency.ParsedValue.Cmp(big.NewRat(123456, 100)) != 0 {
+		t.Error("CURRENCY should be exactly 1234.56", row.Currency)
+	}
+}
+
+type linkedTicket struct {
+	Name       StringField `gxl:"NAME"`
+	TicketLink StringField `gxl:"TICKET,link"`
+}
+
+func TestLinkTagOption(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"TICKET", "NAME"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"T-1", "First"})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{"T-2", "Second"})
+	_ = f.SetCellHyperLink(sheet, "A2", "https://example.com/T-1", "External")
+	path := filepath.Join(t.TempDir(), "links.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	var arr []linkedTicket
+	if err := MakeAndApplySchema(path, sheet, &arr); err != nil {
+		t.Fatal(err)
+	}
+	if arr[0].Name.ParsedValue != "First" || arr[0].TicketLink.ParsedValue != "https://example.com/T-1" {
+		t.Error("unexpected linked ticket", arr[0])
+	}
+	if !arr[1].TicketLink.Empty || arr[1].TicketLink.ParsedValue != "" {
+		t.Error("cell without a hyperlink should be empty", arr[1].TicketLink)
+	}
+	if _, err := makeFieldOptions(reflect.TypeOf(FloatField{}), map[string]string{linkTagOption: ""}); err != ErrInvalidTagOption {
+		t.Error("link should be invalid on a FloatField, got", err)
+	}
+
+	ps, err := parse.MakeParsedSheetWithOptions(f, sheet, parse.ParseOptions{Hyperlinks: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ap, err := parse.AggregateAllSheetsDefaultInfo(*ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr = nil
+	if err := ApplySchemaToAggregatedParse(ap, &arr); err != nil {
+		t.Fatal(err)
+	}
+	if arr[0].TicketLink.ParsedValue != "https://example.com/T-1" {
+		t.Error("aggregated link should be kept", arr[0].TicketLink)
+	}
+}
+
+type flaggedTicket struct {
+	Ticket      StringField `gxl:"TICKET"`
+	Highlighted BoolField   `gxl:"TICKET,style=fill:ffff00"`
+	Done        BoolField   `gxl:"TICKET,style=strike|font:#00FF00"`
+}
+
+func TestStyleTagOption(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"TICKET"})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"T-1"})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{"T-2"})
+	_ = f.SetSheetRow(sheet, "A4", &[]interface{}{"T-3"})
+	highlight, _ := f.NewStyle(`{"fill":{"type":"pattern","color":["#FFFF00"],"pattern":1}}`)
+	struck, _ := f.NewStyle(`{"font":{"strike":true}}`)
+	_ = f.SetCellStyle(sheet, "A2", "A2", highlight)
+	_ = f.SetCellStyle(sheet, "A3", "A3", struck)
+	path := filepath.Join(t.TempDir(), "styles.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	var arr []flaggedTicket
+	if err := MakeAndApplySchema(path, sheet, &arr); err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]bool{{true, false}, {false, true}, {false, false}}
+	for i, row := range arr {
+		if !row.Highlighted.Successful || row.Highlighted.ParsedValue != want[i][0] || row.Done.ParsedValue != want[i][1] {
+			t.Error(row.Ticket.ParsedValue, "unexpected style flags", row.Highlighted, row.Done)
+		}
+	}
+
+	type invalidStyle struct {
+		Ticket StringField `gxl:"TICKET,style=underline"`
+	}
+	var invalid []invalidStyle
+	if err := MakeAndApplySchema(path, sheet, &invalid); err != ErrInvalidTagOption {
+		t.Error("expected ErrInvalidTagOption, got", err)
+	}
+}
+
+func TestMakeSchemaWithOptions(t *testing.T) {
+	path := writeTestWorkbook(t, "SALES", [][]interface{}{
+		{"REGION", "SALES"},
+		{"North", 10},
+		{"South", 20},
+		{"Grand Total", 30},
+	})
+	type sales struct {
+		Region StringField `gxl:"REGION"`
+		Sales  FloatField  `gxl:"SALES"`
+	}
+	sc, err := MakeSchemaWithOptions(path, parse.ParseOptions{TrailingRowPattern: parse.TotalRowPattern})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var arr []sales
+	if err := sc.ApplySchema("SALES", &arr); err != nil {
+		t.Fatal(err)
+	}
+	if len(arr) != 2 || arr[1].Region.ParsedValue != "South" {
+		t.Error("total row should not be a data row", arr)
+	}
+}
@@ -2,7 +2,9 @@ package schema
 
 import (
 	"errors"
+	"github.com/C-Canchola/goexcel/parse"
 	"reflect"
+	"strings"
 )
 
 // taggedFieldMap returns a map from a given reflect.Type
@@ -26,14 +28,15 @@ func taggedFieldMap(v reflect.Value) (map[string]int, error) {
 		field := t.Field(i)
 
 		value, ok := field.Tag.Lookup(TagKey)
-		if !ok {
+		if !ok || isProvenanceTag(value) || isStyleTag(value) {
 			continue
 		}
-		_, exists := m[value]
+		header := tagHeader(value)
+		_, exists := m[header]
 		if exists {
 			return nil, ErrTagsWithSameKey
 		}
-		m[value] = i
+		m[header] = i
 	}
 
 	return m, nil
@@ -54,12 +57,119 @@ func taggedFieldFieldTypeMap(v reflect.Value, taggedFieldMap map[string]int) (ma
 	return m, nil
 }
 
+// Provenance tags fill a field with where a row was read from
+// instead of a column value.
+//	-row is the Excel row number (int)
+//	-sheet is the sheet name (string)
+//	-file is the path of the file (string)
+//	-cell:<header> is the A1 address of the header's cell in the row (string)
+const (
+	provenanceTagPrefix = "-"
+	rowProvenanceTag    = "-row"
+	sheetProvenanceTag  = "-sheet"
+	fileProvenanceTag   = "-file"
+	cellProvenanceTag   = "-cell:"
+)
+
+var ErrInvalidProvenanceTag = errors.New("schema: unknown provenance tag")
+var ErrInvalidProvenanceFieldType = errors.New("schema: provenance field has an invalid type")
+
+func isProvenanceTag(value string) bool {
+	return strings.HasPrefix(value, provenanceTagPrefix)
+}
+
+// cellProvenanceHeader returns the header of a -cell:<header> tag.
+func cellProvenanceHeader(value string) (string, bool) {
+	if !strings.HasPrefix(value, cellProvenanceTag) {
+		return "", false
+	}
+	return strings.TrimPrefix(value, cellProvenanceTag), true
+}
+
+// provenanceFieldMap returns a map of the indices of provenance tagged fields
+// with their tag values.
+func provenanceFieldMap(v reflect.Value) (map[int]string, error) {
+	t := v.Type()
+	if t.Kind() != reflect.Struct {
+		return nil, ErrNotStructType
+	}
+	m := make(map[int]string)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		value, ok := field.Tag.Lookup(TagKey)
+		if !ok || !isProvenanceTag(value) {
+			continue
+		}
+		value = tagHeader(value)
+		wantKind := reflect.String
+		switch value {
+		case rowProvenanceTag:
+			wantKind = reflect.Int
+		case sheetProvenanceTag, fileProvenanceTag:
+		default:
+			if header, ok := cellProvenanceHeader(value); !ok || header == "" {
+				return nil, ErrInvalidProvenanceTag
+			}
+		}
+		if field.Type.Kind() != wantKind {
+			return nil, ErrInvalidProvenanceFieldType
+		}
+		m[i] = value
+	}
+	return m, nil
+}
+
+// styleField is a BoolField set from the style of its header's cell.
+type styleField struct {
+	header  string
+	matches func(parse.CellStyle) bool
+}
+
+// isStyleTag returns whether the tag value has the style option.
+func isStyleTag(value string) bool {
+	_, options := parseTag(value)
+	_, ok := options[styleTagOption]
+	return ok
+}
+
+// styleFieldMap returns a map of the indices of fields with the style tag option.
+// The style option can not be combined with other options.
+func styleFieldMap(v reflect.Value) (map[int]styleField, error) {
+	t := v.Type()
+	if t.Kind() != reflect.Struct {
+		return nil, ErrNotStructType
+	}
+	m := make(map[int]styleField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		value, ok := field.Tag.Lookup(TagKey)
+		if !ok || isProvenanceTag(value) || !isStyleTag(value) {
+			continue
+		}
+		header, options := parseTag(value)
+		if field.Type != reflect.TypeOf(BoolField{}) || len(options) > 1 {
+			return nil, ErrInvalidTagOption
+		}
+		matches, err := parseStyleOption(options[styleTagOption])
+		if err != nil {
+			return nil, err
+		}
+		m[i] = styleField{header: header, matches: matches}
+	}
+	return m, nil
+}
+
 // preProcessor is used to hold the type and tag information
 // of a type which will be parsed from a tabular excel sheet.
 type preProcessor struct {
 	headerFieldMap     map[string]int
 	headerIdxMap       map[int]string
 	taggedFieldTypeMap map[int]reflect.Type
+	fieldOptionMap     map[int]fieldOptions
+	provenanceFieldMap map[int]string
+	styleFieldMap      map[int]styleField
 }
 
 var ErrPreprocessorHasInvalidTaggedFields = errors.New("schema: preprocessor has tagged fields which are not valid")
@@ -79,14 +189,29 @@ func makePreprocessor(v reflect.Value) (preProcessor, error) {
 	if err != nil {
 		return preProcessor{}, err
 	}
+	provenanceFields, err := provenanceFieldMap(v)
+	if err != nil {
+		return preProcessor{}, err
+	}
+	styleFields, err := styleFieldMap(v)
+	if err != nil {
+		return preProcessor{}, err
+	}
 	madePreProcessor := preProcessor{
 		headerFieldMap:     headerFieldMap,
 		headerIdxMap:       headerIdxMap,
 		taggedFieldTypeMap: taggedFieldFieldTypeMap,
+		provenanceFieldMap: provenanceFields,
+		styleFieldMap:      styleFields,
 	}
 	if !preProcessorHasAllValidTaggedTypes(madePreProcessor) {
 		return preProcessor{}, ErrPreprocessorHasInvalidTaggedFields
 	}
+	fieldOptionMap, err := taggedFieldOptionMap(v, headerFieldMap)
+	if err != nil {
+		return preProcessor{}, err
+	}
+	madePreProcessor.fieldOptionMap = fieldOptionMap
 
 	return madePreProcessor, nil
 }
@@ -107,3 +232,31 @@ func (pp preProcessor) getTaggedFieldColumnIndexMap(d sheetDetails) (map[int]int
 	}
 	return idxMap, nil
 }
+
+// getProvenanceCellColumnIndexMap returns a map of key: provenanceFieldIndex value:columnIndex
+// for every -cell provenance field.
+func (pp preProcessor) getProvenanceCellColumnIndexMap(d sheetDetails) map[int]int {
+	colIndices := d.headerExcelColumnIndices()
+	idxMap := make(map[int]int)
+
+	// Can assume valid as validation occurs when creating the tagged field map.
+	for fieldIdx, tag := range pp.provenanceFieldMap {
+		if header, ok := cellProvenanceHeader(tag); ok {
+			idxMap[fieldIdx] = colIndices[header][0]
+		}
+	}
+	return idxMap
+}
+
+// getStyleColumnIndexMap returns a map of key: styleFieldIndex value:columnIndex
+// for every style field.
+func (pp preProcessor) getStyleColumnIndexMap(d sheetDetails) map[int]int {
+	colIndices := d.headerExcelColumnIndices()
+	idxMap := make(map[int]int)
+
+	// Can assume valid as validation occurs before the map is created.
+	for fieldIdx, sf := range pp.styleFieldMap {
+		idxMap[fieldIdx] = colIndices[sf.header][0]
+	}
+	return idxMap
+}
@@ -0,0 +1,159 @@
+package schema
+
+import (
+	"errors"
+	"github.com/C-Canchola/goexcel/parse"
+	"time"
+)
+
+// Record is a dynamically decoded data row which is accessed by header
+// instead of by tagged struct fields.
+// Useful for exploratory tools where the columns are not known ahead of time.
+type Record struct {
+	// Row is the Excel row number the record was read from.
+	Row int
+	// Sheet is the name of the sheet the record was read from.
+	Sheet string
+	// File is the path of the file the record was read from.
+	File string
+
+	shtSc     sheetSchema
+	dataIdx   int
+	headerIdx map[string]int
+	failed    map[string]error
+}
+
+// ErrRecordHeaderDNE is returned when accessing a header which does not exist in a record.
+var ErrRecordHeaderDNE = errors.New("schema: header does not exist in record")
+
+// Headers returns the headers of the record in column order.
+func (r *Record) Headers() []string {
+	return r.shtSc.parsedSheet.Original[0]
+}
+
+// Map returns the originally formatted values of the record keyed by header.
+//	When a header appears more than once, the first column is used.
+func (r *Record) Map() map[string]string {
+	m := make(map[string]string, len(r.headerIdx))
+	for header, colIdx := range r.headerIdx {
+		m[header] = r.shtSc.parsedSheet.Original[r.rowIdx()][colIdx]
+	}
+	return m
+}
+
+// Failed returns the headers of every cell which failed conversion
+// through one of the typed getters along with the error.
+func (r *Record) Failed() map[string]error {
+	return r.failed
+}
+
+// rowIdx returns the index of the record within the parsed sheet.
+func (r *Record) rowIdx() int {
+	return r.dataIdx + ExcelOffset
+}
+
+func (r *Record) column(header string) (int, error) {
+	colIdx, ok := r.headerIdx[header]
+	if !ok {
+		return 0, ErrRecordHeaderDNE
+	}
+	return colIdx, nil
+}
+
+// recordResult remembers a failed conversion for the header.
+func (r *Record) recordResult(header string, err error) error {
+	if err != nil {
+		r.failed[header] = err
+	}
+	return err
+}
+
+// GetString returns the originally formatted value of the header's cell.
+func (r *Record) GetString(header string) (string, error) {
+	colIdx, err := r.column(header)
+	if err != nil {
+		return "", err
+	}
+	return r.shtSc.parsedSheet.ParsedString(r.rowIdx(), colIdx)
+}
+
+// GetFloat parses the decimal formatted value of the header's cell as a float64.
+func (r *Record) GetFloat(header string) (float64, error) {
+	colIdx, err := r.column(header)
+	if err != nil {
+		return 0, err
+	}
+	f, err := r.shtSc.parsedSheet.ParsedFloat(r.rowIdx(), colIdx)
+	return f, r.recordResult(header, err)
+}
+
+// GetInt parses the decimal formatted value of the header's cell as an int.
+func (r *Record) GetInt(header string) (int, error) {
+	colIdx, err := r.column(header)
+	if err != nil {
+		return 0, err
+	}
+	i, err := r.shtSc.parsedSheet.ParsedInt(r.rowIdx(), colIdx)
+	return i, r.recordResult(header, err)
+}
+
+// GetTime parses the decimal formatted value of the header's cell as a time.Time.
+func (r *Record) GetTime(header string) (time.Time, error) {
+	colIdx, err := r.column(header)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := r.shtSc.parsedTime(r.dataIdx, colIdx, parse.DefaultTimeLayouts, time.UTC)
+	return t, r.recordResult(header, err)
+}
+
+// records creates a Record for every data row of the sheet schema.
+func (shtSc sheetSchema) records() ([]Record, error) {
+	sheetDetails, err := shtSc.makeSheetDetails()
+	if err != nil {
+		return nil, err
+	}
+	headerIdx := make(map[string]int)
+	for header, indices := range sheetDetails.headerExcelColumnIndices() {
+		headerIdx[header] = indices[0]
+	}
+	records := make([]Record, 0, sheetDetails.tblDimension.RowCount)
+	for i := 0; i < sheetDetails.tblDimension.RowCount; i++ {
+		records = append(records, Record{
+			Row:       shtSc.excelRow(i),
+			Sheet:     shtSc.sourceSheetName(i),
+			File:      shtSc.filePath(i),
+			shtSc:     shtSc,
+			dataIdx:   i,
+			headerIdx: headerIdx,
+			failed:    make(map[string]error),
+		})
+	}
+	return records, nil
+}
+
+// ApplyRecords decodes every data row of a worksheet as a Record.
+func (sc Schema) ApplyRecords(sheet string) ([]Record, error) {
+	shtSc, err := sc.makeSheetSchema(sheet, false)
+	if err != nil {
+		return nil, err
+	}
+	return shtSc.records()
+}
+
+// RecordsFromParsedSheet decodes every data row of an already parsed sheet as a Record.
+func RecordsFromParsedSheet(ps *parse.ParsedSheet) ([]Record, error) {
+	if ps == nil || len(ps.Original) == 0 {
+		return nil, parse.ErrInvalidData
+	}
+	shtSc := sheetSchema{
+		sheetName:   ps.Name,
+		parsedSheet: ps,
+	}
+	return shtSc.records()
+}
+
+// RecordsFromAggregatedParse decodes every item of an aggregation as a Record.
+func RecordsFromAggregatedParse(ap parse.AggregatedParse) ([]Record, error) {
+	return makeAggregatedSheetSchema(ap).records()
+}
@@ -0,0 +1,42 @@
+package schema
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSchema_ApplyRecords(t *testing.T) {
+	s, err := MakeSchema(filepath.Join("data", "data.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := s.ApplyRecords("STRING_ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 9 {
+		t.Fatal("expected 9 records, got", len(records))
+	}
+	rec := &records[0]
+	if id, err := rec.GetString("ID"); err != nil || id != "hello" {
+		t.Error("first ID should be hello, is", id, err)
+	}
+	if date, err := rec.GetTime("DATE"); err != nil || date.Year() != 2020 {
+		t.Error("first DATE should be in 2020, is", date, err)
+	}
+	if _, err := rec.GetFloat("ID"); err == nil {
+		t.Error("ID should not parse as a float")
+	}
+	if _, ok := rec.Failed()["ID"]; !ok {
+		t.Error("ID should be remembered as a failed conversion")
+	}
+	if _, err := rec.GetString("MISSING"); err != ErrRecordHeaderDNE {
+		t.Error("expected ErrRecordHeaderDNE, got", err)
+	}
+	if rec.Row != 2 || rec.Sheet != "STRING_ID" {
+		t.Error("unexpected record provenance", rec.Row, rec.Sheet)
+	}
+	if rec.Map()["ID"] != "hello" {
+		t.Error("record map should contain ID")
+	}
+}
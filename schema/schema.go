@@ -3,7 +3,10 @@ package schema
 import (
 	"github.com/360EntSecGroup-Skylar/excelize/v2"
 	"github.com/C-Canchola/goexcel/parse"
+	"math/big"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -21,7 +24,10 @@ const ExcelOffset = 1
 // Schema is used to provide parsing to a single excel file reference
 // in order to populate struct slices.
 type Schema struct {
-	f *excelize.File
+	f    *excelize.File
+	path string
+	// opts are used when parsing each sheet.
+	opts parse.ParseOptions
 }
 
 // MakeSchema creates a Schema for a given excel file.
@@ -33,22 +39,43 @@ func MakeSchema(filePath string) (Schema, error) {
 		return Schema{}, err
 	}
 	return Schema{
-		f: f,
+		f:    f,
+		path: filePath,
 	}, nil
 }
 
+// MakeSchemaWithOptions creates a Schema for a given excel file which parses
+// each sheet with the given options e.g. to stop before total rows.
+func MakeSchemaWithOptions(filePath string, opts parse.ParseOptions) (Schema, error) {
+	sc, err := MakeSchema(filePath)
+	if err != nil {
+		return Schema{}, err
+	}
+	sc.opts = opts
+	return sc, nil
+}
+
 type sheetSchema struct {
 	sheetName string
 
 	schema      Schema
 	parsedSheet *parse.ParsedSheet
+	// aggItems holds the source of each data row when applying to an aggregation.
+	aggItems []parse.AggItem
 }
 
+// Blank cells set Empty on every field type. Whether a blank cell is Successful
+// depends on the field's blank tag option, see fieldOptions.
+// Pointers to field types are also valid and are left nil for blank cells
+// unless a default tag option is given.
+
 // TimeField is a valid type for Schema parsing.
 // Its parsed value is a time.Time value.
 type TimeField struct {
 	ParsedValue time.Time
 	Successful  bool
+	// Empty is true when the cell is blank.
+	Empty       bool
 	StringValue string
 	HeaderValue string
 }
@@ -58,6 +85,8 @@ type TimeField struct {
 type FloatField struct {
 	ParsedValue float64
 	Successful  bool
+	// Empty is true when the cell is blank.
+	Empty       bool
 	StringValue string
 	HeaderValue string
 }
@@ -67,6 +96,8 @@ type FloatField struct {
 type IntField struct {
 	ParsedValue int
 	Successful  bool
+	// Empty is true when the cell is blank.
+	Empty       bool
 	StringValue string
 	HeaderValue string
 }
@@ -76,14 +107,58 @@ type IntField struct {
 type StringField struct {
 	ParsedValue string
 	Successful  bool
+	// Empty is true when the cell is blank.
+	Empty       bool
 	HeaderValue string
 }
 
-func (sc Schema) makeSheetSchema(sheetName string) (sheetSchema, error) {
-	parsedSheet, err := parse.MakeParsedSheet(sc.f, sheetName)
+// BoolField is a valid type for Schema parsing.
+// Its parsed value is a bool value read from TRUE/FALSE, Y/N, yes/no or 1/0 cells.
+type BoolField struct {
+	ParsedValue bool
+	Successful  bool
+	// Empty is true when the cell is blank.
+	Empty       bool
+	StringValue string
+	HeaderValue string
+}
+
+// DurationField is a valid type for Schema parsing.
+// Its parsed value is a time.Duration value read from Excel times or "hh:mm:ss" text.
+type DurationField struct {
+	ParsedValue time.Duration
+	Successful  bool
+	// Empty is true when the cell is blank.
+	Empty       bool
+	StringValue string
+	HeaderValue string
+}
+
+// DecimalField is a valid type for Schema parsing.
+// Its parsed value is an exact *big.Rat value which avoids float64 rounding.
+// ParsedValue is nil when unsuccessful.
+type DecimalField struct {
+	ParsedValue *big.Rat
+	Successful  bool
+	// Empty is true when the cell is blank.
+	Empty       bool
+	StringValue string
+	HeaderValue string
+}
+
+// makeSheetSchema parses the sheet with the schema's options.
+// Hyperlinks are only loaded when links is true, see linksRequired.
+func (sc Schema) makeSheetSchema(sheetName string, links bool) (sheetSchema, error) {
+	opts := sc.opts
+	opts.Hyperlinks = opts.Hyperlinks || links
+	parsedSheet, err := parse.MakeParsedSheetWithOptions(sc.f, sheetName, opts)
 	if err != nil {
 		return sheetSchema{}, err
 	}
+	if sc.path != "" {
+		parsedSheet.Path = sc.path
+		parsedSheet.FileName = filepath.Base(sc.path)
+	}
 	return sheetSchema{
 		sheetName:   sheetName,
 		schema:      sc,
@@ -91,9 +166,34 @@ func (sc Schema) makeSheetSchema(sheetName string) (sheetSchema, error) {
 	}, nil
 }
 
+// isBlank returns whether a cell value should be treated as blank.
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+// fieldIsBlank returns whether a cell value is blank for the field type.
+//	StringField keeps whitespace as its text so only empty cells are blank.
+func fieldIsBlank(fieldType reflect.Type, s string) bool {
+	if fieldType == reflect.TypeOf(StringField{}) {
+		return s == ""
+	}
+	return isBlank(s)
+}
+
 func (shtSc sheetSchema) makeTimeField(rowIdx int, fieldIdx int, colIdx int, pp preProcessor) TimeField {
 	s, _ := shtSc.parsedSheet.ParsedString(rowIdx+ExcelOffset, colIdx)
-	t, err := shtSc.parsedSheet.ParsedTime(rowIdx+ExcelOffset, colIdx)
+	fo := pp.fieldOptionMap[fieldIdx]
+	if isBlank(s) {
+		t, success := fo.blankTime()
+		return TimeField{
+			ParsedValue: t,
+			Successful:  success,
+			Empty:       true,
+			StringValue: s,
+			HeaderValue: pp.headerIdxMap[fieldIdx],
+		}
+	}
+	t, err := shtSc.parsedTime(rowIdx, colIdx, fo.timeLayouts, fo.location)
 	success := err == nil
 	return TimeField{
 		ParsedValue: t,
@@ -105,7 +205,18 @@ func (shtSc sheetSchema) makeTimeField(rowIdx int, fieldIdx int, colIdx int, pp
 
 func (shtSc sheetSchema) makeFloatField(rowIdx int, fieldIdx int, colIdx int, pp preProcessor) FloatField {
 	s, _ := shtSc.parsedSheet.ParsedString(rowIdx+ExcelOffset, colIdx)
-	f, err := shtSc.parsedSheet.ParsedFloat(rowIdx+ExcelOffset, colIdx)
+	fo := pp.fieldOptionMap[fieldIdx]
+	if isBlank(s) {
+		f, success := fo.blankFloat()
+		return FloatField{
+			ParsedValue: f,
+			Successful:  success,
+			Empty:       true,
+			StringValue: s,
+			HeaderValue: pp.headerIdxMap[fieldIdx],
+		}
+	}
+	f, err := shtSc.parsedFloat(rowIdx, colIdx, fo)
 	success := err == nil
 	return FloatField{
 		ParsedValue: f,
@@ -117,7 +228,18 @@ func (shtSc sheetSchema) makeFloatField(rowIdx int, fieldIdx int, colIdx int, pp
 
 func (shtSc sheetSchema) makeIntField(rowIdx int, fieldIdx int, colIdx int, pp preProcessor) IntField {
 	s, _ := shtSc.parsedSheet.ParsedString(rowIdx+ExcelOffset, colIdx)
-	i, err := shtSc.parsedSheet.ParsedInt(rowIdx+ExcelOffset, colIdx)
+	fo := pp.fieldOptionMap[fieldIdx]
+	if isBlank(s) {
+		f, success := fo.blankFloat()
+		return IntField{
+			ParsedValue: int(f),
+			Successful:  success,
+			Empty:       true,
+			StringValue: s,
+			HeaderValue: pp.headerIdxMap[fieldIdx],
+		}
+	}
+	i, err := shtSc.parsedInt(rowIdx, colIdx, fo)
 	success := err == nil
 	return IntField{
 		ParsedValue: i,
@@ -129,6 +251,19 @@ func (shtSc sheetSchema) makeIntField(rowIdx int, fieldIdx int, colIdx int, pp p
 
 func (shtSc sheetSchema) makeStringField(rowIdx int, fieldIdx int, colIdx int, pp preProcessor) StringField {
 	s, _ := shtSc.parsedSheet.ParsedString(rowIdx+ExcelOffset, colIdx)
+	if pp.fieldOptionMap[fieldIdx].link {
+		// Cells without a hyperlink are treated as blank.
+		s, _ = shtSc.parsedSheet.Hyperlink(rowIdx+ExcelOffset, colIdx)
+	}
+	if fieldIsBlank(reflect.TypeOf(StringField{}), s) {
+		v, success := pp.fieldOptionMap[fieldIdx].blankString()
+		return StringField{
+			ParsedValue: v,
+			Successful:  success,
+			Empty:       true,
+			HeaderValue: pp.headerIdxMap[fieldIdx],
+		}
+	}
 
 	return StringField{
 		ParsedValue: s,
@@ -137,6 +272,79 @@ func (shtSc sheetSchema) makeStringField(rowIdx int, fieldIdx int, colIdx int, p
 	}
 }
 
+func (shtSc sheetSchema) makeBoolField(rowIdx int, fieldIdx int, colIdx int, pp preProcessor) BoolField {
+	s, _ := shtSc.parsedSheet.ParsedString(rowIdx+ExcelOffset, colIdx)
+	if isBlank(s) {
+		b, success := pp.fieldOptionMap[fieldIdx].blankBool()
+		return BoolField{
+			ParsedValue: b,
+			Successful:  success,
+			Empty:       true,
+			StringValue: s,
+			HeaderValue: pp.headerIdxMap[fieldIdx],
+		}
+	}
+	b, err := shtSc.parsedSheet.ParsedBool(rowIdx+ExcelOffset, colIdx)
+	success := err == nil
+	return BoolField{
+		ParsedValue: b,
+		Successful:  success,
+		StringValue: s,
+		HeaderValue: pp.headerIdxMap[fieldIdx],
+	}
+}
+
+func (shtSc sheetSchema) makeDurationField(rowIdx int, fieldIdx int, colIdx int, pp preProcessor) DurationField {
+	s, _ := shtSc.parsedSheet.ParsedString(rowIdx+ExcelOffset, colIdx)
+	if isBlank(s) {
+		d, success := pp.fieldOptionMap[fieldIdx].blankDuration()
+		return DurationField{
+			ParsedValue: d,
+			Successful:  success,
+			Empty:       true,
+			StringValue: s,
+			HeaderValue: pp.headerIdxMap[fieldIdx],
+		}
+	}
+	d, err := shtSc.parsedSheet.ParsedDuration(rowIdx+ExcelOffset, colIdx)
+	success := err == nil
+	return DurationField{
+		ParsedValue: d,
+		Successful:  success,
+		StringValue: s,
+		HeaderValue: pp.headerIdxMap[fieldIdx],
+	}
+}
+
+func (shtSc sheetSchema) makeDecimalField(rowIdx int, fieldIdx int, colIdx int, pp preProcessor) DecimalField {
+	s, _ := shtSc.parsedSheet.ParsedString(rowIdx+ExcelOffset, colIdx)
+	fo := pp.fieldOptionMap[fieldIdx]
+	if isBlank(s) {
+		rat, success := fo.blankRat()
+		return DecimalField{
+			ParsedValue: rat,
+			Successful:  success,
+			Empty:       true,
+			StringValue: s,
+			HeaderValue: pp.headerIdxMap[fieldIdx],
+		}
+	}
+	var rat *big.Rat
+	var err error
+	if fo.numberLocale == nil {
+		rat, err = shtSc.parsedSheet.ParsedRat(rowIdx+ExcelOffset, colIdx)
+	} else {
+		rat, err = shtSc.parsedSheet.ParsedRatLocale(rowIdx+ExcelOffset, colIdx, *fo.numberLocale)
+	}
+	success := err == nil
+	return DecimalField{
+		ParsedValue: rat,
+		Successful:  success,
+		StringValue: s,
+		HeaderValue: pp.headerIdxMap[fieldIdx],
+	}
+}
+
 // MakeAndApplySchema creates a schema based on the given file path
 // and attempts the application on the given sheet and value (pointer to slice of whatever
 // type which contains the tagged struct fields to be read from the excel file)
@@ -151,11 +359,84 @@ func MakeAndApplySchema(filePath string, sheet string, v interface{})error{
 // ApplySchema attempts to apply the schema to a worksheet
 // and struct slice based upon the tags of the slice's elements
 func (sc Schema) ApplySchema(sheet string, v interface{}) error {
-	sheetSchema, err := sc.makeSheetSchema(sheet)
+	sheetSchema, err := sc.makeSheetSchema(sheet, linksRequired(v))
 	if err != nil {
 		return err
 	}
+	return sheetSchema.apply(v)
+}
+
+// ApplySchemaToParsedSheet attempts to apply a schema to an already parsed sheet.
+// This allows a sheet to be cleaned up (e.g. RemoveDuplicateColumnsFromRow) before decoding.
+// The parsed sheet is not modified.
+func ApplySchemaToParsedSheet(ps *parse.ParsedSheet, v interface{}) error {
+	if ps == nil || len(ps.Original) == 0 {
+		return parse.ErrInvalidData
+	}
+	shtSc := sheetSchema{
+		sheetName:   ps.Name,
+		parsedSheet: ps,
+	}
+	return shtSc.apply(v)
+}
+
+// ApplySchemaToAggregatedParse attempts to apply a schema to the items of an aggregation.
+// Provenance tags are filled from each item's source sheet.
+func ApplySchemaToAggregatedParse(ap parse.AggregatedParse, v interface{}) error {
+	return makeAggregatedSheetSchema(ap).apply(v)
+}
+
+// makeAggregatedSheetSchema creates a sheet schema from the header and items of an aggregation.
+func makeAggregatedSheetSchema(ap parse.AggregatedParse) sheetSchema {
+	original := make([][]string, 0, len(ap.Items)+1)
+	decimal := make([][]string, 0, len(ap.Items)+1)
+	original = append(original, ap.Header)
+	decimal = append(decimal, ap.Header)
+	hyperlinks := make(map[string]string)
+	for i, item := range ap.Items {
+		original = append(original, item.OriginalFormat)
+		decimal = append(decimal, item.DecimalFormat)
+		for colIdx, target := range item.Hyperlinks {
+			if target == "" {
+				continue
+			}
+			// Links are keyed by their position within the aggregation.
+			axis, _ := excelize.CoordinatesToCellName(colIdx+ExcelOffset, i+ExcelOffset+ExcelOffset)
+			hyperlinks[axis] = target
+		}
+	}
+	return sheetSchema{
+		parsedSheet: &parse.ParsedSheet{
+			Original:      original,
+			DecimalFormat: decimal,
+			Hyperlinks:    hyperlinks,
+		},
+		aggItems: ap.Items,
+	}
+}
+
+// linksRequired returns whether any field of the struct slice's elements has the link tag option.
+// Invalid values return false and are rejected when the schema is applied.
+func linksRequired(v interface{}) bool {
+	vSlicePtr := reflect.ValueOf(v)
+	if vSlicePtr.Kind() != reflect.Ptr || !typeIsStructSlice(vSlicePtr.Elem()) {
+		return false
+	}
+	pp, err := makePreprocessor(reflect.New(vSlicePtr.Elem().Type().Elem()).Elem())
+	if err != nil {
+		return false
+	}
+	for _, fo := range pp.fieldOptionMap {
+		if fo.link {
+			return true
+		}
+	}
+	return false
+}
 
+// apply attempts to apply the sheet schema to the struct slice
+// based upon the tags of the slice's elements
+func (shtSc sheetSchema) apply(v interface{}) error {
 	vSlicePtr := reflect.ValueOf(v)
 	vSlice := vSlicePtr.Elem()
 
@@ -170,7 +451,7 @@ func (sc Schema) ApplySchema(sheet string, v interface{}) error {
 		return err
 	}
 
-	sheetDetails, err := sheetSchema.makeSheetDetails()
+	sheetDetails, err := shtSc.makeSheetDetails()
 	if err != nil {
 		return err
 	}
@@ -180,8 +461,13 @@ func (sc Schema) ApplySchema(sheet string, v interface{}) error {
 		return err
 	}
 
+	cellFieldMap := preProcessor.getProvenanceCellColumnIndexMap(sheetDetails)
+	styleColMap := preProcessor.getStyleColumnIndexMap(sheetDetails)
+
 	for i := 0; i < sheetDetails.tblDimension.RowCount; i++ {
-		newSliceEl := sheetSchema.makeNewSliceEl(sliceEl, preProcessor, taggedFieldMap, i)
+		newSliceEl := shtSc.makeNewSliceEl(sliceEl, preProcessor, taggedFieldMap, i)
+		shtSc.setProvenanceFields(newSliceEl, preProcessor, cellFieldMap, i)
+		shtSc.setStyleFields(newSliceEl, preProcessor, styleColMap, i)
 		vSlice.Set(reflect.Append(vSlice, newSliceEl))
 	}
 	return nil
@@ -196,26 +482,154 @@ func (shtSc sheetSchema) makeNewSliceEl(el reflect.Type, pp preProcessor, tagged
 
 	for fieldIdx, colIdx := range taggedFieldMap {
 		fieldPtr := newElVal.Field(fieldIdx)
+		fieldType := pp.taggedFieldTypeMap[fieldIdx]
+
+		if fieldType.Kind() != reflect.Ptr {
+			fieldPtr.Set(shtSc.makeField(fieldType, rowIdx, fieldIdx, colIdx, pp))
+			continue
+		}
+		s, _ := shtSc.parsedSheet.ParsedString(rowIdx+ExcelOffset, colIdx)
+		if fieldIsBlank(fieldType.Elem(), s) && !pp.fieldOptionMap[fieldIdx].hasDefault() {
+			continue
+		}
+		fieldValPtr := reflect.New(fieldType.Elem())
+		fieldValPtr.Elem().Set(shtSc.makeField(fieldType.Elem(), rowIdx, fieldIdx, colIdx, pp))
+		fieldPtr.Set(fieldValPtr)
+	}
+	return newElVal
+}
+
+// makeField applies the parsing function of the field type to a data row's cell.
+func (shtSc sheetSchema) makeField(fieldType reflect.Type, rowIdx int, fieldIdx int, colIdx int, pp preProcessor) reflect.Value {
+	switch fieldType {
+
+	case reflect.TypeOf(TimeField{}):
+		return reflect.ValueOf(shtSc.makeTimeField(rowIdx, fieldIdx, colIdx, pp))
 
-		switch pp.taggedFieldTypeMap[fieldIdx] {
+	case reflect.TypeOf(FloatField{}):
+		return reflect.ValueOf(shtSc.makeFloatField(rowIdx, fieldIdx, colIdx, pp))
 
-		case reflect.TypeOf(TimeField{}):
-			timeField := shtSc.makeTimeField(rowIdx, fieldIdx, colIdx, pp)
-			fieldPtr.Set(reflect.ValueOf(timeField))
+	case reflect.TypeOf(IntField{}):
+		return reflect.ValueOf(shtSc.makeIntField(rowIdx, fieldIdx, colIdx, pp))
 
-		case reflect.TypeOf(FloatField{}):
-			floatField := shtSc.makeFloatField(rowIdx, fieldIdx, colIdx, pp)
-			fieldPtr.Set(reflect.ValueOf(floatField))
+	case reflect.TypeOf(StringField{}):
+		return reflect.ValueOf(shtSc.makeStringField(rowIdx, fieldIdx, colIdx, pp))
 
-		case reflect.TypeOf(IntField{}):
-			intField := shtSc.makeIntField(rowIdx, fieldIdx, colIdx, pp)
-			fieldPtr.Set(reflect.ValueOf(intField))
+	case reflect.TypeOf(BoolField{}):
+		return reflect.ValueOf(shtSc.makeBoolField(rowIdx, fieldIdx, colIdx, pp))
 
-		case reflect.TypeOf(StringField{}):
-			stringField := shtSc.makeStringField(rowIdx, fieldIdx, colIdx, pp)
-			fieldPtr.Set(reflect.ValueOf(stringField))
+	case reflect.TypeOf(DurationField{}):
+		return reflect.ValueOf(shtSc.makeDurationField(rowIdx, fieldIdx, colIdx, pp))
 
+	case reflect.TypeOf(DecimalField{}):
+		return reflect.ValueOf(shtSc.makeDecimalField(rowIdx, fieldIdx, colIdx, pp))
+
+	}
+	return reflect.Zero(fieldType)
+}
+
+// excelRow returns the Excel row number of a data row.
+func (shtSc sheetSchema) excelRow(rowIdx int) int {
+	if shtSc.aggItems != nil {
+		return shtSc.aggItems[rowIdx].RowIdx + ExcelOffset
+	}
+	return shtSc.parsedSheet.RowOrigin(rowIdx + ExcelOffset)
+}
+
+// cellAddress returns the worksheet address of a data row's cell.
+func (shtSc sheetSchema) cellAddress(rowIdx, colIdx int) string {
+	var addr string
+	if shtSc.aggItems != nil {
+		addr, _ = shtSc.aggItems[rowIdx].CellAddress(colIdx)
+	} else {
+		addr, _ = shtSc.parsedSheet.CellAddress(rowIdx+ExcelOffset, colIdx)
+	}
+	return addr
+}
+
+// parsedFloat parses a data row's cell as a float64 reading formatted text
+// when the field has the number option.
+func (shtSc sheetSchema) parsedFloat(rowIdx, colIdx int, fo fieldOptions) (float64, error) {
+	if fo.numberLocale == nil {
+		return shtSc.parsedSheet.ParsedFloat(rowIdx+ExcelOffset, colIdx)
+	}
+	return shtSc.parsedSheet.ParsedFloatLocale(rowIdx+ExcelOffset, colIdx, *fo.numberLocale)
+}
+
+// parsedInt parses a data row's cell as an int reading formatted text
+// when the field has the number option.
+func (shtSc sheetSchema) parsedInt(rowIdx, colIdx int, fo fieldOptions) (int, error) {
+	if fo.numberLocale == nil {
+		return shtSc.parsedSheet.ParsedInt(rowIdx+ExcelOffset, colIdx)
+	}
+	return shtSc.parsedSheet.ParsedIntLocale(rowIdx+ExcelOffset, colIdx, *fo.numberLocale)
+}
+
+// parsedTime parses a data row's cell as a time.Time using the date system
+// of the workbook the row was read from.
+func (shtSc sheetSchema) parsedTime(rowIdx, colIdx int, layouts []string, loc *time.Location) (time.Time, error) {
+	if shtSc.aggItems == nil {
+		return shtSc.parsedSheet.ParsedTimeIn(rowIdx+ExcelOffset, colIdx, layouts, loc)
+	}
+	item := shtSc.aggItems[rowIdx]
+	return parse.ParseTimeCell(item.DecimalFormat[colIdx], item.OriginalFormat[colIdx], item.Date1904, layouts, loc)
+}
+
+// sourceSheetName returns the name of the sheet a data row was parsed from.
+func (shtSc sheetSchema) sourceSheetName(rowIdx int) string {
+	if shtSc.aggItems != nil {
+		return shtSc.aggItems[rowIdx].SheetName
+	}
+	return shtSc.parsedSheet.Name
+}
+
+// filePath returns the path of the file a data row was parsed from
+// falling back to the file name when no path is known.
+func (shtSc sheetSchema) filePath(rowIdx int) string {
+	path, fileName := shtSc.parsedSheet.Path, shtSc.parsedSheet.FileName
+	if shtSc.aggItems != nil {
+		path, fileName = shtSc.aggItems[rowIdx].FilePath, shtSc.aggItems[rowIdx].FileName
+	}
+	if path != "" {
+		return path
+	}
+	return fileName
+}
+
+// setProvenanceFields populates the provenance tagged fields of a new slice element.
+func (shtSc sheetSchema) setProvenanceFields(elVal reflect.Value, pp preProcessor, cellFieldMap map[int]int, rowIdx int) {
+	for fieldIdx, tag := range pp.provenanceFieldMap {
+		fieldPtr := elVal.Field(fieldIdx)
+
+		switch tag {
+		case rowProvenanceTag:
+			fieldPtr.SetInt(int64(shtSc.excelRow(rowIdx)))
+		case sheetProvenanceTag:
+			fieldPtr.SetString(shtSc.sourceSheetName(rowIdx))
+		case fileProvenanceTag:
+			fieldPtr.SetString(shtSc.filePath(rowIdx))
+		default:
+			fieldPtr.SetString(shtSc.cellAddress(rowIdx, cellFieldMap[fieldIdx]))
 		}
 	}
-	return newElVal
+}
+
+// setStyleFields sets every style field from the style of its header's cell.
+// Style fields are unsuccessful when the data has no cells e.g. an aggregation.
+func (shtSc sheetSchema) setStyleFields(elVal reflect.Value, pp preProcessor, styleColMap map[int]int, rowIdx int) {
+	for fieldIdx, sf := range pp.styleFieldMap {
+		colIdx := styleColMap[fieldIdx]
+		s, _ := shtSc.parsedSheet.ParsedString(rowIdx+ExcelOffset, colIdx)
+		field := BoolField{
+			Empty:       isBlank(s),
+			StringValue: s,
+			HeaderValue: sf.header,
+		}
+		if shtSc.parsedSheet.Cells != nil {
+			cell, err := shtSc.parsedSheet.Cell(rowIdx+ExcelOffset, colIdx)
+			field.ParsedValue = err == nil && sf.matches(cell.Style)
+			field.Successful = err == nil
+		}
+		elVal.Field(fieldIdx).Set(reflect.ValueOf(field))
+	}
 }
@@ -2,7 +2,10 @@ package schema
 
 import (
 	"fmt"
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/C-Canchola/goexcel/parse"
 	"path/filepath"
+	"strconv"
 	"testing"
 )
 
@@ -67,3 +70,126 @@ func TestSchema_LargeRead(t *testing.T) {
 	fmt.Println(itemArr[0])
 
 }
+
+type IdDataWithProvenance struct {
+	Id       StringField `gxl:"ID"`
+	Row      int         `gxl:"-row"`
+	Sheet    string      `gxl:"-sheet"`
+	File     string      `gxl:"-file"`
+	DateCell string      `gxl:"-cell:DATE"`
+}
+
+func TestSchema_ApplySchemaProvenance(t *testing.T) {
+	var idArr []IdDataWithProvenance
+	path := filepath.Join("data", "data.xlsx")
+	if err := MakeAndApplySchema(path, "STRING_ID", &idArr); err != nil {
+		t.Fatal(err)
+	}
+	if len(idArr) == 0 {
+		t.Fatal("expected rows to be parsed")
+	}
+	first := idArr[0]
+	if first.Row != 2 {
+		t.Error("first data row should be excel row 2, is", first.Row)
+	}
+	if first.Sheet != "STRING_ID" {
+		t.Error("sheet should be STRING_ID, is", first.Sheet)
+	}
+	if first.File != path {
+		t.Error("file should be", path, "is", first.File)
+	}
+	if first.DateCell != "B2" {
+		t.Error("date cell should be B2, is", first.DateCell)
+	}
+	if idArr[1].DateCell != "B3" {
+		t.Error("second date cell should be B3, is", idArr[1].DateCell)
+	}
+}
+
+type invalidProvenance struct {
+	Row string `gxl:"-row"`
+}
+
+func TestInvalidProvenanceFieldType(t *testing.T) {
+	var arr []invalidProvenance
+	err := MakeAndApplySchema(filepath.Join("data", "data.xlsx"), "STRING_ID", &arr)
+	if err != ErrInvalidProvenanceFieldType {
+		t.Error("expected ErrInvalidProvenanceFieldType, got", err)
+	}
+}
+
+type PrefixedIdData struct {
+	RowNumber IntField    `gxl:"ROW_NUMBER"`
+	Id        StringField `gxl:"ID"`
+	Sheet     string      `gxl:"-sheet"`
+}
+
+func TestApplySchemaToParsedSheet(t *testing.T) {
+	ps, err := parse.MakeParsedSheetFromPath(filepath.Join("data", "data.xlsx"), "STRING_ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	ps.ApplyPrefixColumn("ROW_NUMBER", func() string {
+		count++
+		return strconv.Itoa(count)
+	})
+	var arr []PrefixedIdData
+	if err := ApplySchemaToParsedSheet(ps, &arr); err != nil {
+		t.Fatal(err)
+	}
+	if len(arr) != 9 {
+		t.Fatal("expected 9 rows, got", len(arr))
+	}
+	// The mapper is called for each format so the original format holds the first count.
+	if arr[8].RowNumber.StringValue != "9" || arr[0].Id.ParsedValue != "hello" {
+		t.Error("unexpected decoded values", arr[0], arr[8])
+	}
+}
+
+func TestApplySchemaToAggregatedParse(t *testing.T) {
+	ps, err := parse.MakeParsedSheetFromPath(filepath.Join("data", "data.xlsx"), "STRING_ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	agg, err := parse.AggregateAllSheetsDefaultInfo(*ps, *ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var arr []IdDataWithProvenance
+	if err := ApplySchemaToAggregatedParse(agg, &arr); err != nil {
+		t.Fatal(err)
+	}
+	if len(arr) != 18 {
+		t.Fatal("expected 18 rows, got", len(arr))
+	}
+	if arr[9].Row != 2 || arr[9].Sheet != "STRING_ID" || arr[9].Id.ParsedValue != "hello" {
+		t.Error("second sheet's first row should restart at excel row 2", arr[9])
+	}
+}
+
+type regionSales struct {
+	Region    StringField `gxl:"REGION"`
+	Sales     FloatField  `gxl:"SALES"`
+	Row       int         `gxl:"-row"`
+	SalesCell string      `gxl:"-cell:SALES"`
+}
+
+func TestSchema_ApplySchemaToRegion(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetCellValue(sheet, "A1", "Management Pack")
+	_ = f.SetSheetRow(sheet, "C3", &[]interface{}{"REGION", "SALES"})
+	_ = f.SetSheetRow(sheet, "C4", &[]interface{}{"North", 10})
+	regions, err := parse.MakeParsedRegions(f, sheet, parse.ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var arr []regionSales
+	if err := ApplySchemaToParsedSheet(regions[1], &arr); err != nil {
+		t.Fatal(err)
+	}
+	if len(arr) != 1 || arr[0].Sales.ParsedValue != 10 || arr[0].Row != 4 || arr[0].SalesCell != "D4" {
+		t.Error("unexpected region row", arr)
+	}
+}
@@ -17,8 +17,12 @@ func typeIsStructSlice(v reflect.Value) bool {
 }
 
 func typeIsValidTaggedType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
 	switch t {
-	case reflect.TypeOf(TimeField{}), reflect.TypeOf(IntField{}), reflect.TypeOf(FloatField{}), reflect.TypeOf(StringField{}):
+	case reflect.TypeOf(TimeField{}), reflect.TypeOf(IntField{}), reflect.TypeOf(FloatField{}), reflect.TypeOf(StringField{}),
+		reflect.TypeOf(BoolField{}), reflect.TypeOf(DurationField{}), reflect.TypeOf(DecimalField{}):
 		return true
 	default:
 		return false
@@ -40,6 +44,8 @@ var ErrTaggedHeaderNotUnique = errors.New("schema: tagged header appears more th
 // will result in a valid schema parse.
 // The following must be true:
 //		Every tagged fields value should exist in the header row exactly once.
+//		Every -cell provenance header should exist in the header row exactly once.
+//		Every style field header should exist in the header row exactly once.
 func preProcessorIsValidWithHeaderRow(p preProcessor, d sheetDetails) error {
 	sheetHeaderColIndices := d.headerExcelColumnIndices()
 	for taggedHeader := range p.headerFieldMap {
@@ -51,5 +57,23 @@ func preProcessorIsValidWithHeaderRow(p preProcessor, d sheetDetails) error {
 			return ErrTaggedHeaderNotUnique
 		}
 	}
+	headers := make([]string, 0, len(p.provenanceFieldMap)+len(p.styleFieldMap))
+	for _, tag := range p.provenanceFieldMap {
+		if header, ok := cellProvenanceHeader(tag); ok {
+			headers = append(headers, header)
+		}
+	}
+	for _, sf := range p.styleFieldMap {
+		headers = append(headers, sf.header)
+	}
+	for _, header := range headers {
+		indices, ok := sheetHeaderColIndices[header]
+		if !ok {
+			return ErrTaggedHeaderDNEInData
+		}
+		if len(indices) > 1 {
+			return ErrTaggedHeaderNotUnique
+		}
+	}
 	return nil
 }
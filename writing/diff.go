@@ -0,0 +1,226 @@
+package writing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/C-Canchola/goexcel/parse"
+	"strconv"
+	"time"
+)
+
+// DiffStatusHeader is the header of the first column of a written diff which holds each row's status.
+const DiffStatusHeader = "STATUS"
+
+// The statuses of the rows of a written diff.
+const (
+	DiffAdded   = "ADDED"
+	DiffRemoved = "REMOVED"
+	DiffChanged = "CHANGED"
+)
+
+// Fill colors of the highlighted cells of a written diff.
+const (
+	diffAddedColor   = "#C6EFCE"
+	diffRemovedColor = "#FFC7CE"
+	diffChangedColor = "#FFEB9C"
+)
+
+// diffCommentAuthor is the author of the comments holding the old values of changed cells.
+const diffCommentAuthor = "diff"
+
+// ErrDiffStatusHeader is returned when a sheet of a diff has a column headed DiffStatusHeader.
+var ErrDiffStatusHeader = errors.New("writing: diff sheet has a column headed " + DiffStatusHeader)
+
+// diffCell is a value of a written diff with the number format of the cell it came from.
+type diffCell struct {
+	value interface{}
+	// numFmt is the code of the number format of a number cell. Empty for other cells.
+	numFmt string
+	// date is true when the value is a time written with the default date format.
+	date bool
+}
+
+// diffValue returns the value of the cell at the pair of indices of a sheet.
+// Number and date cells are written from their decimal format with the cell's number format
+// when the sheet has cells. Other values are converted from their original format.
+//	Dates are read as times in the sheet's date system so they are written in the writer's.
+func diffValue(ps *parse.ParsedSheet, r, c int) diffCell {
+	if cell, err := ps.Cell(r, c); err == nil && (cell.Type == parse.CellTypeNumber || cell.Type == parse.CellTypeDate) {
+		numFmt := cell.NumFmtCode
+		if numFmt == "General" {
+			numFmt = ""
+		}
+		if cell.IsDate() {
+			if t, err := ps.ParsedTime(r, c); err == nil {
+				return diffCell{value: t, numFmt: numFmt, date: numFmt == ""}
+			}
+		}
+		if f, err := strconv.ParseFloat(ps.DecimalFormat[r][c], 64); err == nil {
+			return diffCell{value: f, numFmt: numFmt}
+		}
+	}
+	v := convertStringValToInterfaceVal(ps.Original[r][c])
+	_, date := v.(time.Time)
+	return diffCell{value: v, date: date}
+}
+
+// diffStyleKey identifies a style of a written diff.
+type diffStyleKey struct {
+	color, numFmt string
+	date          bool
+}
+
+// diffStyle returns the style filling a cell with the color and keeping its number format.
+// Styles are created once and kept in the cache.
+func (w *FileWriter) diffStyle(cache map[diffStyleKey]int, color string, cell diffCell) (int, error) {
+	key := diffStyleKey{color: color, numFmt: cell.numFmt, date: cell.date}
+	if style, ok := cache[key]; ok {
+		return style, nil
+	}
+	style := &excelize.Style{}
+	if color != "" {
+		style.Fill = excelize.Fill{Type: "pattern", Color: []string{color}, Pattern: 1}
+	}
+	if cell.numFmt != "" {
+		numFmt := cell.numFmt
+		style.CustomNumFmt = &numFmt
+	}
+	if cell.date {
+		style.NumFmt = dateNumFmt
+	}
+	id, err := w.file.NewStyle(style)
+	if err != nil {
+		return 0, err
+	}
+	cache[key] = id
+	return id, nil
+}
+
+// diffHeader returns the header of a written diff: the status column, every header of the new
+// sheet and then the headers found only in the old sheet.
+func diffHeader(diff parse.SheetDiff) ([]string, error) {
+	header := []string{DiffStatusHeader}
+	header = append(header, diff.New.Original[0]...)
+	header = append(header, diff.RemovedHeaders...)
+	for _, h := range header[1:] {
+		if h == DiffStatusHeader {
+			return nil, ErrDiffStatusHeader
+		}
+	}
+	return header, nil
+}
+
+// diffRow returns the values of a row of a sheet in the order of the data headers, which
+// exclude the status column. Columns the sheet does not have are nil.
+func diffRow(dataHeader []string, ps *parse.ParsedSheet, r int) []*diffCell {
+	row := make([]*diffCell, len(dataHeader))
+	for i, h := range dataHeader {
+		if c, err := ps.ColumnIndex(h); err == nil {
+			cell := diffValue(ps, r, c)
+			row[i] = &cell
+		}
+	}
+	return row
+}
+
+// WriteDiffToSheet writes every added, removed and changed row of the diff to the given sheet.
+// The first column holds the row's status and the remaining columns the new values, or the old
+// values of a removed row.
+//	Number and date cells keep their number format when the sheets were parsed from a
+//	worksheet. Other values are written from their original format.
+//	Added and removed rows are filled green and red. The changed cells of a changed row are
+//	filled yellow with a comment holding the old value.
+//	ErrDiffStatusHeader is returned when either sheet has a column headed DiffStatusHeader.
+func (w *FileWriter) WriteDiffToSheet(diff parse.SheetDiff, sheet string) error {
+	if diff.Old == nil || diff.New == nil || len(diff.New.Original) == 0 {
+		return parse.ErrInvalidData
+	}
+	header, err := diffHeader(diff)
+	if err != nil {
+		return err
+	}
+	dataHeader := header[1:]
+	w.populateEmptySheet(sheet)
+	for colIdx, h := range header {
+		coords, _ := excelize.CoordinatesToCellName(colIdx+excelOffset, excelOffset)
+		if err := w.file.SetCellValue(sheet, coords, h); err != nil {
+			return err
+		}
+	}
+
+	styles := make(map[diffStyleKey]int)
+	rowIdx := headerOffset
+	// writeRow writes the status and values of a row filling it with the color, or only
+	// the cells of the changed headers when changed is not nil.
+	writeRow := func(status string, values []*diffCell, color string, changed map[string]bool) error {
+		rowIdx++
+		cells := append([]*diffCell{{value: status}}, values...)
+		for colIdx, cell := range cells {
+			if cell == nil {
+				continue
+			}
+			coords, _ := excelize.CoordinatesToCellName(colIdx+excelOffset, rowIdx)
+			if err := w.setCellValue(sheet, coords, cell.value); err != nil {
+				return err
+			}
+			fill := color
+			if changed != nil && (colIdx == 0 || !changed[header[colIdx]]) {
+				fill = ""
+			}
+			if fill == "" && cell.numFmt == "" {
+				continue
+			}
+			style, err := w.diffStyle(styles, fill, *cell)
+			if err != nil {
+				return err
+			}
+			if err := w.file.SetCellStyle(sheet, coords, coords, style); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, change := range diff.Changed {
+		changed := make(map[string]bool)
+		for _, cc := range change.Changes {
+			changed[cc.Header] = true
+		}
+		if err := writeRow(DiffChanged, diffRow(dataHeader, diff.New, change.NewRow), diffChangedColor, changed); err != nil {
+			return err
+		}
+		for _, cc := range change.Changes {
+			colIdx := -1
+			for i, h := range dataHeader {
+				if h == cc.Header {
+					colIdx = i + 1
+					break
+				}
+			}
+			if colIdx < 0 {
+				return fmt.Errorf("%w: %s", parse.ErrUnknownHeader, cc.Header)
+			}
+			coords, _ := excelize.CoordinatesToCellName(colIdx+excelOffset, rowIdx)
+			comment, _ := json.Marshal(struct {
+				Author string `json:"author"`
+				Text   string `json:"text"`
+			}{Author: diffCommentAuthor, Text: "was " + cc.OldValue})
+			if err := w.file.AddComment(sheet, coords, string(comment)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, r := range diff.Added {
+		if err := writeRow(DiffAdded, diffRow(dataHeader, diff.New, r), diffAddedColor, nil); err != nil {
+			return err
+		}
+	}
+	for _, r := range diff.Removed {
+		if err := writeRow(DiffRemoved, diffRow(dataHeader, diff.Old, r), diffRemovedColor, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/C-Canchola/goexcel/parse"
 	"os"
 	"strconv"
+	"time"
 )
 
 // tableColumnRatio is used to space column widths
@@ -28,7 +30,10 @@ const nextSheetOffset = 1
 type FileWriter struct {
 	file            *excelize.File
 	hasWrittenSheet bool
-
+	// date1904 is true when the workbook uses the 1904 date system.
+	date1904 bool
+	// dateStyle is the style applied to times written as 1904 serial numbers.
+	dateStyle int
 }
 
 
@@ -51,9 +56,51 @@ func MakeFileWriterFromExisting(path string) (*FileWriter, error) {
 	return &FileWriter{
 		file:            f,
 		hasWrittenSheet: true,
+		date1904:        parse.WorkbookDate1904(f),
 	}, nil
 }
 
+// dateNumFmt is the built in m/d/yy h:mm number format excelize applies to written times.
+const dateNumFmt = 22
+
+// cellValue converts time values to serial numbers of the 1904 date system when
+// the workbook uses it as excelize always writes times in the 1900 date system.
+// The returned bool is true when the value was converted.
+func (w *FileWriter) cellValue(v interface{}) (interface{}, bool) {
+	t, ok := v.(time.Time)
+	if !ok || !w.date1904 {
+		return v, false
+	}
+	return parse.TimeToExcelDate(t, true), true
+}
+
+// setCellValue sets the cell value applying a date style to converted time values.
+func (w *FileWriter) setCellValue(sheet, axis string, v interface{}) error {
+	v, converted := w.cellValue(v)
+	if err := w.file.SetCellValue(sheet, axis, v); err != nil {
+		return err
+	}
+	if !converted {
+		return nil
+	}
+	if w.dateStyle == 0 {
+		style, err := w.file.NewStyle(&excelize.Style{NumFmt: dateNumFmt})
+		if err != nil {
+			return err
+		}
+		w.dateStyle = style
+	}
+	return w.file.SetCellStyle(sheet, axis, axis, w.dateStyle)
+}
+
+// rowValues converts every value of a row with cellValue.
+func (w *FileWriter) rowValues(row []interface{}) []interface{} {
+	for i := range row {
+		row[i], _ = w.cellValue(row[i])
+	}
+	return row
+}
+
 func pathDoesNotExist(path string) bool {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return true
@@ -116,7 +163,7 @@ func (w *FileWriter) WriteDataToSheet(header []string, data [][]interface{}, she
 	for rowIdx, row := range data {
 		for colIdx, c := range row {
 			coords, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx+2)
-			if err := w.file.SetCellValue(sheet, coords, c); err != nil {
+			if err := w.setCellValue(sheet, coords, c); err != nil {
 				return err
 			}
 		}
@@ -140,7 +187,7 @@ func (w *FileWriter)WriteStringDataToSheet(header []string, data[][]string, shee
 
 	for rowIdx, row := range data{
 		coords, _ = excelize.CoordinatesToCellName(1, rowIdx + 2)
-		if err := sw.SetRow(coords, convertStringArrToInterfaceArr(row)); err != nil{
+		if err := sw.SetRow(coords, w.rowValues(convertStringArrToInterfaceArr(row))); err != nil{
 			return err
 		}
 	}
@@ -230,7 +277,7 @@ func (iw *IndexedWriter) getCurrentIndexCellAddress(col int)string{
 
 // writeIndexedValue writes the provided writeVal to the current row with the given column.
 func (iw *IndexedWriter)writeIndexedValue(col int, writeVal interface{})error{
-	return iw.fw.file.SetCellValue(indexSheetName, iw.getCurrentIndexCellAddress(col), writeVal)
+	return iw.fw.setCellValue(indexSheetName, iw.getCurrentIndexCellAddress(col), writeVal)
 }
 
 // writeAdditionalDetails writes the sheet name and any additional details to the index tab
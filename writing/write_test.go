@@ -1,11 +1,15 @@
 package writing
 
 import (
+	"errors"
 	"fmt"
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/C-Canchola/goexcel/parse"
 	"github.com/C-Canchola/goexcel/schema"
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 )
 
 type IdData struct {
@@ -142,3 +146,202 @@ func TestLargeWrite(t *testing.T){
 		t.Error(err)
 	}
 }
+
+func TestWriteDate1904(t *testing.T) {
+	f := excelize.NewFile()
+	f.WorkBook.WorkbookPr.Date1904 = true
+	path := filepath.Join(t.TempDir(), "date1904.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	writer, err := MakeFileWriterFromExisting(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	if err := writer.WriteDataToSheet([]string{"DATE"}, [][]interface{}{{want}}, "DATES"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.SaveFile(path, true); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := parse.MakeParsedSheetFromPath(path, "DATES")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ps.ParsedTime(1, 0); err != nil || !got.Equal(want) {
+		t.Error("written date should read back as", want, "is", got, err)
+	}
+}
+
+func TestWriteStringDataDate1904(t *testing.T) {
+	f := excelize.NewFile()
+	f.WorkBook.WorkbookPr.Date1904 = true
+	path := filepath.Join(t.TempDir(), "stringDate1904.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	writer, err := MakeFileWriterFromExisting(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteStringDataToSheet([]string{"DATE"}, [][]string{{"11-09-20"}}, "DATES"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.SaveFile(path, true); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := parse.MakeParsedSheetFromPath(path, "DATES")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2020, 11, 9, 0, 0, 0, 0, time.UTC)
+	if got, err := ps.ParsedTime(1, 0); err != nil || !got.Equal(want) {
+		t.Error("written date string should read back as", want, "is", got, err)
+	}
+}
+
+func TestWriteDiffToSheet(t *testing.T) {
+	old := &parse.ParsedSheet{
+		Original:      [][]string{{"ID", "PRICE"}, {"1", "10"}, {"2", "20"}},
+		DecimalFormat: [][]string{{"ID", "PRICE"}, {"1", "10"}, {"2", "20"}},
+	}
+	new := &parse.ParsedSheet{
+		Original:      [][]string{{"ID", "PRICE"}, {"1", "15"}, {"3", "30"}},
+		DecimalFormat: [][]string{{"ID", "PRICE"}, {"1", "15"}, {"3", "30"}},
+	}
+	diff, err := parse.Diff(old, new, "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := MakeNewFileWriter()
+	if err := writer.WriteDiffToSheet(diff, "DIFF"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "diff.xlsx")
+	if err := writer.SaveFile(path, true); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := parse.MakeParsedSheetWithOptions(writer.file, "DIFF", parse.ParseOptions{Comments: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ps.Original) != "[[STATUS ID PRICE] [CHANGED 1 15] [ADDED 3 30] [REMOVED 2 20]]" {
+		t.Error("unexpected diff sheet", ps.Original)
+	}
+	if comment, ok := ps.Comment(1, 2); !ok || comment.Text != "was 10" {
+		t.Error("changed cell should hold the old value in a comment, got", comment)
+	}
+	if cell, _ := ps.Cell(1, 2); cell.Style.FillColor != "FFEB9C" {
+		t.Error("changed cell should be highlighted, got", cell.Style.FillColor)
+	}
+	if cell, _ := ps.Cell(1, 1); cell.Style.IsFilled() {
+		t.Error("unchanged cell should not be highlighted")
+	}
+	if cell, _ := ps.Cell(3, 0); cell.Style.FillColor != "FFC7CE" {
+		t.Error("removed row should be highlighted, got", cell.Style.FillColor)
+	}
+
+	diff.Changed[0].Changes[0].Header = "MISSING"
+	if err := MakeNewFileWriter().WriteDiffToSheet(diff, "DIFF"); !errors.Is(err, parse.ErrUnknownHeader) {
+		t.Error("expected ErrUnknownHeader for a missing changed column, got", err)
+	}
+	clashing := &parse.ParsedSheet{
+		Original:      [][]string{{"ID", "STATUS"}, {"1", "open"}},
+		DecimalFormat: [][]string{{"ID", "STATUS"}, {"1", "open"}},
+	}
+	diff, err = parse.Diff(clashing, clashing, "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := MakeNewFileWriter().WriteDiffToSheet(diff, "DIFF"); !errors.Is(err, ErrDiffStatusHeader) {
+		t.Error("expected ErrDiffStatusHeader, got", err)
+	}
+}
+
+func TestWriteDiffToSheetDate1904(t *testing.T) {
+	dateFmt := "yyyy-mm-dd"
+	// dateSheet parses a new 1904 workbook with a due date formatted as a date.
+	dateSheet := func(due time.Time) *parse.ParsedSheet {
+		f := excelize.NewFile()
+		f.WorkBook.WorkbookPr.Date1904 = true
+		sheet := f.GetSheetName(0)
+		_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"ID", "DUE"})
+		_ = f.SetSheetRow(sheet, "A2", &[]interface{}{"a", parse.TimeToExcelDate(due, true)})
+		style, _ := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFmt})
+		_ = f.SetCellStyle(sheet, "B2", "B2", style)
+		ps, err := parse.MakeParsedSheet(f, sheet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ps
+	}
+	was := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	want := time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC)
+	diff, err := parse.Diff(dateSheet(was), dateSheet(want), "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := MakeNewFileWriter()
+	if err := writer.WriteDiffToSheet(diff, "DIFF"); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := parse.MakeParsedSheet(writer.file, "DIFF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ps.ParsedTime(1, 2); err != nil || !got.Equal(want) {
+		t.Error("changed date should read back as", want, "is", got, err)
+	}
+	if cell, _ := ps.Cell(1, 2); cell.NumFmtCode != dateFmt || cell.Style.FillColor != "FFEB9C" {
+		t.Error("changed date should keep its format and be highlighted, got", cell)
+	}
+}
+
+func TestWriteDiffToSheetNumberFormats(t *testing.T) {
+	currency := "$#,##0.00"
+	// priceSheet parses a new workbook of prices formatted as currency.
+	priceSheet := func(rows ...[]interface{}) *parse.ParsedSheet {
+		f := excelize.NewFile()
+		sheet := f.GetSheetName(0)
+		_ = f.SetSheetRow(sheet, "A1", &[]interface{}{"ID", "PRICE"})
+		for i, row := range rows {
+			_ = f.SetSheetRow(sheet, "A"+strconv.Itoa(i+2), &row)
+		}
+		style, _ := f.NewStyle(&excelize.Style{CustomNumFmt: &currency})
+		_ = f.SetCellStyle(sheet, "B2", "B"+strconv.Itoa(len(rows)+1), style)
+		ps, err := parse.MakeParsedSheet(f, sheet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ps
+	}
+	old := priceSheet([]interface{}{"a", 10}, []interface{}{"b", 20})
+	new := priceSheet([]interface{}{"a", 12.5})
+	diff, err := parse.Diff(old, new, "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := MakeNewFileWriter()
+	if err := writer.WriteDiffToSheet(diff, "DIFF"); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := parse.MakeParsedSheet(writer.file, "DIFF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ps.Original) != "[[STATUS ID PRICE] [CHANGED a 12.5] [REMOVED b 20]]" {
+		t.Error("unexpected diff sheet", ps.Original)
+	}
+	if v, err := ps.ParsedFloat(1, 2); err != nil || v != 12.5 {
+		t.Error("changed price should be written as a number, got", v, err)
+	}
+	if cell, _ := ps.Cell(1, 2); cell.Type != parse.CellTypeNumber || cell.NumFmtCode != currency || cell.Style.FillColor != "FFEB9C" {
+		t.Error("changed price should be a highlighted number, got", cell)
+	}
+	if cell, _ := ps.Cell(2, 2); cell.Style.FillColor != "FFC7CE" || cell.NumFmtCode != currency {
+		t.Error("removed price should keep its format and fill, got", cell)
+	}
+}